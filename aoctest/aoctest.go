@@ -0,0 +1,135 @@
+// Package aoctest turns a day's declared examples into subtests.
+package aoctest
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"adventofcode2024/runner"
+)
+
+// Answer is an expected result. The zero value means the part is not
+// checked for that example.
+type Answer struct {
+	value int
+	set   bool
+}
+
+func Want(n int) Answer {
+	return Answer{value: n, set: true}
+}
+
+type Example struct {
+	Name  string
+	Input string
+	Part1 Answer
+	Part2 Answer
+}
+
+// ErrorCase is an input, plus optional command line arguments, on which
+// the day's run must fail.
+type ErrorCase struct {
+	Name  string
+	Input string
+	Args  []string
+}
+
+type Suite struct {
+	Day      runner.Day
+	Examples []Example
+	Errors   []ErrorCase
+}
+
+// Run checks every example through the solvers directly and through the
+// runner, then checks that a missing input and every error case fail.
+func (s Suite) Run(t *testing.T) {
+	t.Helper()
+	for _, ex := range s.Examples {
+		t.Run(ex.Name, func(t *testing.T) {
+			s.checkSolvers(t, ex)
+			s.checkRunner(t, ex)
+		})
+	}
+
+	t.Run("missing input", func(t *testing.T) {
+		if _, err := s.run(nil, fstest.MapFS{}); err == nil {
+			t.Error("Expected error for missing input file")
+		}
+	})
+
+	for _, ec := range s.Errors {
+		t.Run(ec.Name, func(t *testing.T) {
+			fsys := fstest.MapFS{"input": {Data: []byte(ec.Input)}}
+			if _, err := s.run(ec.Args, fsys); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func (s Suite) checkSolvers(t *testing.T, ex Example) {
+	t.Helper()
+	parts := []struct {
+		solve runner.Solver
+		want  Answer
+	}{
+		{s.Day.Part1, ex.Part1},
+		{s.Day.Part2, ex.Part2},
+	}
+	for i, part := range parts {
+		if !part.want.set {
+			continue
+		}
+		result, err := part.solve(context.Background(), strings.NewReader(ex.Input))
+		if err != nil {
+			t.Errorf("Part%d failed: %v", i+1, err)
+			continue
+		}
+		if result != part.want.value {
+			t.Errorf("Part%d: expected %d, got %d", i+1, part.want.value, result)
+		}
+	}
+}
+
+func (s Suite) checkRunner(t *testing.T, ex Example) {
+	t.Helper()
+	if !ex.Part1.set || !ex.Part2.set {
+		return
+	}
+
+	output, err := s.run(nil, fstest.MapFS{"input": {Data: []byte(ex.Input)}})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	expected := runner.TextResult(1, ex.Part1.value) + runner.TextResult(2, ex.Part2.value)
+	if output != expected {
+		t.Errorf("Expected output:\n%s\nGot:\n%s", expected, output)
+	}
+}
+
+func (s Suite) run(args []string, fsys fstest.MapFS) (string, error) {
+	var stdout, stderr bytes.Buffer
+	err := runner.Run(context.Background(), s.Day, args, runner.Env{
+		Stdin:  strings.NewReader(""),
+		Stdout: &stdout,
+		Stderr: &stderr,
+		FS:     fsys,
+	})
+	return stdout.String(), err
+}
+
+// TempFile writes content to a file that is removed with the test and
+// returns its name.
+func TempFile(t testing.TB, content string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "input")
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
@@ -0,0 +1,115 @@
+package aoctest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"adventofcode2024/runner"
+)
+
+func countLines(_ context.Context, r io.Reader) (int, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	if strings.Contains(string(content), "bad") {
+		return 0, errors.New("bad input")
+	}
+	return strings.Count(string(content), "\n"), nil
+}
+
+func TestSuite(t *testing.T) {
+	Suite{
+		Day: runner.Day{Number: 1, Part1: countLines, Part2: countLines},
+		Examples: []Example{
+			{Name: "both parts", Input: "a\nb\n", Part1: Want(2), Part2: Want(2)},
+			{Name: "part one only", Input: "a\n", Part1: Want(1)},
+			{Name: "part two only", Input: "", Part2: Want(0)},
+		},
+		Errors: []ErrorCase{
+			{Name: "solver error", Input: "bad\n"},
+			{Name: "bad flag", Input: "a\n", Args: []string{"-nope"}},
+		},
+	}.Run(t)
+}
+
+func TestWant(t *testing.T) {
+	if (Answer{}).set {
+		t.Error("the zero Answer should not be set")
+	}
+	if answer := Want(0); !answer.set || answer.value != 0 {
+		t.Errorf("Want(0) = %+v", answer)
+	}
+}
+
+func TestTempFile(t *testing.T) {
+	filename := TempFile(t, "1 2\n")
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "1 2\n" {
+		t.Errorf("TempFile wrote %q", content)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	Golden(t, runner.Day{Number: 1, Part1: countLines, Part2: countLines})
+}
+
+func TestGoldenDir_Update(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "example1.txt"), []byte("a\nb\nc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "example1.args"), []byte("-part 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	day := runner.Day{Number: 1, Part1: countLines, Part2: countLines}
+
+	*update = true
+	defer func() { *update = false }()
+	GoldenDir(t, day, dir)
+	*update = false
+
+	expected, err := os.ReadFile(filepath.Join(dir, "example1.expected"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(expected) != "Part1 result:  3\n" {
+		t.Errorf("-update wrote %q", expected)
+	}
+
+	GoldenDir(t, day, dir)
+}
+
+func TestGoldenDir_Unanswered(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "example2.txt"), []byte("..#\n#..\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	failing := func(context.Context, io.Reader) (int, error) {
+		return 0, errors.New("not an input")
+	}
+	GoldenDir(t, runner.Day{Number: 1, Part1: failing, Part2: failing}, dir)
+}
+
+func TestReadArgs(t *testing.T) {
+	args, err := readArgs(filepath.Join("testdata", "part2.args"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(args, " ") != "-part 2" {
+		t.Errorf("readArgs() = %q", args)
+	}
+
+	args, err = readArgs(filepath.Join("testdata", "missing.args"))
+	if err != nil || args != nil {
+		t.Errorf("readArgs() on a missing file = %q, %v", args, err)
+	}
+}
@@ -0,0 +1,94 @@
+package aoctest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"adventofcode2024/runner"
+)
+
+var update = flag.Bool("update", false, "rewrite the .expected golden files in testdata")
+
+// Golden runs the day on every testdata/<name>.txt input and compares the
+// output with testdata/<name>.expected. Extra runner flags, such as
+// "-part 1" for examples that only cover the first part, can be listed in
+// testdata/<name>.args. With -update the .expected files are rewritten
+// from the current output instead.
+func Golden(t *testing.T, day runner.Day) {
+	t.Helper()
+	GoldenDir(t, day, "testdata")
+}
+
+func GoldenDir(t *testing.T, day runner.Day, dir string) {
+	t.Helper()
+	inputs, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Skipf("no examples in %s", dir)
+	}
+
+	fsys := os.DirFS(dir)
+	for _, path := range inputs {
+		name := strings.TrimSuffix(filepath.Base(path), ".txt")
+		t.Run(name, func(t *testing.T) {
+			expectedPath := filepath.Join(dir, name+".expected")
+			args, err := readArgs(filepath.Join(dir, name+".args"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			args = append(args, "-input", name+".txt")
+
+			expected, err := os.ReadFile(expectedPath)
+			if errors.Is(err, fs.ErrNotExist) && !*update {
+				// Not every block copied from a description is an input,
+				// so examples without an answer are not even run.
+				t.Skipf("no %s yet, run go test -update to create it", expectedPath)
+			}
+			if err != nil && !*update {
+				t.Fatal(err)
+			}
+
+			var stdout, stderr bytes.Buffer
+			err = runner.Run(context.Background(), day, args, runner.Env{
+				Stdin:  strings.NewReader(""),
+				Stdout: &stdout,
+				Stderr: &stderr,
+				FS:     fsys,
+			})
+			if err != nil {
+				t.Fatalf("Run failed: %v\n%s", err, stderr.String())
+			}
+
+			if *update {
+				if err := os.WriteFile(expectedPath, stdout.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if stdout.String() != string(expected) {
+				t.Errorf("Expected output (%s):\n%s\nGot:\n%s", expectedPath, expected, stdout.String())
+			}
+		})
+	}
+}
+
+func readArgs(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(content)), nil
+}
@@ -0,0 +1,234 @@
+// Package client talks to the Advent of Code website: it downloads puzzle
+// inputs with the user's session cookie and keeps them in a local cache so
+// that the site is asked for each one only once.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"adventofcode2024/input"
+)
+
+const (
+	DefaultBaseURL   = "https://adventofcode.com"
+	DefaultUserAgent = "github.com/serginator/adventofcode2024 tooling"
+	Year             = 2024
+
+	// DefaultMinInterval is the shortest time between two requests to
+	// the site, also across processes.
+	DefaultMinInterval = 5 * time.Second
+)
+
+var ErrNoSession = errors.New("no session token: set AOC_SESSION or write it to the session config file")
+
+type Client struct {
+	BaseURL     string
+	Session     string
+	UserAgent   string
+	CacheDir    string
+	MinInterval time.Duration
+	HTTP        *http.Client
+
+	mu sync.Mutex
+}
+
+// FromEnv builds a client from the environment:
+//
+//   - AOC_BASE_URL overrides the site, e.g. for a local stand-in
+//   - AOC_SESSION holds the session cookie, falling back to the file
+//     aoc/session in the user's config directory
+//   - AOC_CACHE_DIR overrides the cache, aoc in the user's cache directory
+//     by default
+//   - AOC_USER_AGENT overrides the User-Agent header
+//   - AOC_MIN_INTERVAL overrides the rate limit, as a duration like "2s"
+func FromEnv() (*Client, error) {
+	c := &Client{
+		BaseURL:     DefaultBaseURL,
+		UserAgent:   DefaultUserAgent,
+		MinInterval: DefaultMinInterval,
+		HTTP:        http.DefaultClient,
+	}
+	if url := os.Getenv("AOC_BASE_URL"); url != "" {
+		c.BaseURL = url
+	}
+	if agent := os.Getenv("AOC_USER_AGENT"); agent != "" {
+		c.UserAgent = agent
+	}
+	if interval := os.Getenv("AOC_MIN_INTERVAL"); interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil {
+			return nil, fmt.Errorf("AOC_MIN_INTERVAL: %w", err)
+		}
+		c.MinInterval = d
+	}
+
+	c.Session = os.Getenv("AOC_SESSION")
+	if c.Session == "" {
+		if dir, err := os.UserConfigDir(); err == nil {
+			content, err := os.ReadFile(filepath.Join(dir, "aoc", "session"))
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return nil, err
+			}
+			c.Session = strings.TrimSpace(string(content))
+		}
+	}
+
+	c.CacheDir = os.Getenv("AOC_CACHE_DIR")
+	if c.CacheDir == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		c.CacheDir = filepath.Join(dir, "aoc")
+	}
+	return c, nil
+}
+
+// Input returns the puzzle input for day, from the cache if it has already
+// been downloaded.
+func (c *Client) Input(ctx context.Context, day int) ([]byte, error) {
+	cached := c.cachePath(day, "input")
+	if content, err := os.ReadFile(cached); err == nil {
+		return content, nil
+	}
+	if c.Session == "" {
+		return nil, ErrNoSession
+	}
+
+	content, err := c.get(ctx, fmt.Sprintf("/%d/day/%d/input", Year, day))
+	if err != nil {
+		return nil, err
+	}
+	return content, c.cache(cached, content)
+}
+
+// Page returns the puzzle page for day. It is cached too, but since the
+// page grows a second part once the first is solved, refresh downloads it
+// again. Without a session the page only has the first part.
+func (c *Client) Page(ctx context.Context, day int, refresh bool) ([]byte, error) {
+	cached := c.cachePath(day, "puzzle.html")
+	if content, err := os.ReadFile(cached); err == nil && !refresh {
+		return content, nil
+	}
+
+	content, err := c.get(ctx, fmt.Sprintf("/%d/day/%d", Year, day))
+	if err != nil {
+		return nil, err
+	}
+	return content, c.cache(cached, content)
+}
+
+// PageURL is the address of the puzzle page for day.
+func (c *Client) PageURL(day int) string {
+	return fmt.Sprintf("%s/%d/day/%d", strings.TrimSuffix(c.BaseURL, "/"), Year, day)
+}
+
+func (c *Client) cachePath(day int, name string) string {
+	return filepath.Join(c.CacheDir, fmt.Sprint(Year), fmt.Sprintf("day%02d", day), name)
+}
+
+func (c *Client) cache(filename string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, content, 0600)
+}
+
+func (c *Client) get(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.BaseURL, "/")+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req)
+}
+
+// do sends req, with the session cookie if there is one, once the rate
+// limit allows it, and returns the body of a successful response.
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	if err := c.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+	if c.Session != "" {
+		req.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+	}
+
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// wait sleeps until MinInterval has passed since the last request. The time
+// of the last request is the modification time of a file in the cache, so
+// that separate invocations of the tools share the limit.
+func (c *Client) wait(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stamp := filepath.Join(c.CacheDir, "last-request")
+	if info, err := os.Stat(stamp); err == nil {
+		if delay := c.MinInterval - time.Since(info.ModTime()); delay > 0 {
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	if err := os.MkdirAll(c.CacheDir, 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(stamp, nil, 0600); err != nil {
+		return err
+	}
+	now := time.Now()
+	return os.Chtimes(stamp, now, now)
+}
+
+// SaveInput returns a fetcher for runner.Env that downloads a day's input
+// and also saves it to filename, so later runs read it from disk.
+func SaveInput(filename string) func(ctx context.Context, day int) ([]byte, error) {
+	return func(ctx context.Context, day int) ([]byte, error) {
+		c, err := FromEnv()
+		if err != nil {
+			return nil, err
+		}
+		content, err := c.Input(ctx, day)
+		if err != nil {
+			return nil, err
+		}
+		// A day may still have the old input mapped, so it is replaced
+		// rather than truncated.
+		return content, input.Replace(filename, func(w io.Writer) error {
+			_, err := w.Write(content)
+			return err
+		})
+	}
+}
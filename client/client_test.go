@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeSite serves day inputs like the real site and counts requests.
+func fakeSite(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "secret" {
+			http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+			return
+		}
+		if r.UserAgent() != DefaultUserAgent {
+			t.Errorf("unexpected User-Agent %q", r.UserAgent())
+		}
+		switch r.URL.Path {
+		case "/2024/day/1/input":
+			w.Write([]byte("3   4\n4   3\n"))
+		case "/2024/day/1":
+			fmt.Fprintf(w, "<html>request %d</html>", requests.Load())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func newClient(t *testing.T, baseURL string) *Client {
+	t.Helper()
+	return &Client{
+		BaseURL:   baseURL,
+		Session:   "secret",
+		UserAgent: DefaultUserAgent,
+		CacheDir:  t.TempDir(),
+	}
+}
+
+func TestInput(t *testing.T) {
+	server, requests := fakeSite(t)
+	c := newClient(t, server.URL)
+
+	for i := 0; i < 2; i++ {
+		content, err := c.Input(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(content) != "3   4\n4   3\n" {
+			t.Errorf("unexpected input %q", content)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected the second call to hit the cache, got %d requests", n)
+	}
+}
+
+func TestPage(t *testing.T) {
+	server, _ := fakeSite(t)
+	c := newClient(t, server.URL)
+
+	steps := []struct {
+		refresh  bool
+		expected string
+	}{
+		{refresh: false, expected: "<html>request 1</html>"},
+		{refresh: false, expected: "<html>request 1</html>"},
+		{refresh: true, expected: "<html>request 2</html>"},
+	}
+	for i, step := range steps {
+		page, err := c.Page(context.Background(), 1, step.refresh)
+		if err != nil {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		}
+		if string(page) != step.expected {
+			t.Errorf("step %d: got %q, want %q", i, page, step.expected)
+		}
+	}
+	if url := c.PageURL(1); url != server.URL+"/2024/day/1" {
+		t.Errorf("PageURL() = %q", url)
+	}
+}
+
+func TestInput_Errors(t *testing.T) {
+	server, _ := fakeSite(t)
+
+	tests := []struct {
+		name    string
+		session string
+		day     int
+		message string
+	}{
+		{name: "no session", day: 1, message: ErrNoSession.Error()},
+		{name: "bad session", session: "wrong", day: 1, message: "Please log in"},
+		{name: "not found", session: "secret", day: 25, message: "404"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newClient(t, server.URL)
+			c.Session = tt.session
+			_, err := c.Input(context.Background(), tt.day)
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("expected an error containing %q, got %v", tt.message, err)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	server, _ := fakeSite(t)
+	c := newClient(t, server.URL)
+	c.MinInterval = 100 * time.Millisecond
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if _, err := c.get(context.Background(), "/2024/day/1/input"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < c.MinInterval {
+		t.Errorf("two requests took %v, expected at least %v", elapsed, c.MinInterval)
+	}
+
+	// The limit is shared through the cache directory.
+	other := newClient(t, server.URL)
+	other.CacheDir = c.CacheDir
+	other.MinInterval = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := other.get(ctx, "/2024/day/1/input"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected to wait for the limit, got %v", err)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("AOC_BASE_URL", "http://localhost:1234")
+	t.Setenv("AOC_SESSION", "secret")
+	t.Setenv("AOC_CACHE_DIR", "/tmp/aoc-cache")
+	t.Setenv("AOC_USER_AGENT", "")
+	t.Setenv("AOC_MIN_INTERVAL", "2s")
+
+	c, err := FromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.BaseURL != "http://localhost:1234" || c.Session != "secret" || c.CacheDir != "/tmp/aoc-cache" || c.UserAgent != DefaultUserAgent || c.MinInterval != 2*time.Second {
+		t.Errorf("unexpected client %+v", c)
+	}
+}
+
+func TestFromEnv_InvalidInterval(t *testing.T) {
+	t.Setenv("AOC_MIN_INTERVAL", "soon")
+	if _, err := FromEnv(); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"adventofcode2024/history"
+)
+
+type Response struct {
+	Outcome history.Outcome
+	// Wait is how long the site wants us to wait before the next answer,
+	// when it says so.
+	Wait time.Duration
+	// Message is the text of the response article, without markup.
+	Message string
+}
+
+// Submit posts answer for one part of a day and parses the page the site
+// answers with.
+func (c *Client) Submit(ctx context.Context, day, part, answer int) (Response, error) {
+	if c.Session == "" {
+		return Response{}, ErrNoSession
+	}
+	form := url.Values{"level": {strconv.Itoa(part)}, "answer": {strconv.Itoa(answer)}}
+	endpoint := fmt.Sprintf("%s/%d/day/%d/answer", strings.TrimSuffix(c.BaseURL, "/"), Year, day)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Response{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := c.do(req)
+	if err != nil {
+		return Response{}, err
+	}
+	return ParseResponse(body), nil
+}
+
+var (
+	articlePattern = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagPattern     = regexp.MustCompile(`<[^>]*>`)
+	spacePattern   = regexp.MustCompile(`\s+`)
+	waitPattern    = regexp.MustCompile(`You have (?:(\d+)m )?(\d+)s left to wait`)
+)
+
+func ParseResponse(page []byte) Response {
+	text := string(page)
+	if match := articlePattern.FindStringSubmatch(text); match != nil {
+		text = match[1]
+	}
+	text = html.UnescapeString(tagPattern.ReplaceAllString(text, ""))
+	text = strings.TrimSpace(spacePattern.ReplaceAllString(text, " "))
+
+	resp := Response{Outcome: history.Unknown, Message: text}
+	switch {
+	case strings.Contains(text, "That's the right answer"):
+		resp.Outcome = history.Correct
+	case strings.Contains(text, "your answer is too high"):
+		resp.Outcome = history.TooHigh
+	case strings.Contains(text, "your answer is too low"):
+		resp.Outcome = history.TooLow
+	case strings.Contains(text, "That's not the right answer"):
+		resp.Outcome = history.Wrong
+	case strings.Contains(text, "You gave an answer too recently"):
+		resp.Outcome = history.Wait
+	case strings.Contains(text, "You don't seem to be solving the right level"):
+		resp.Outcome = history.AlreadySolved
+	}
+	if match := waitPattern.FindStringSubmatch(text); match != nil {
+		minutes, _ := strconv.Atoi(match[1])
+		seconds, _ := strconv.Atoi(match[2])
+		resp.Wait = time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	}
+	return resp
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"adventofcode2024/history"
+)
+
+const page = `<!DOCTYPE html>
+<html lang="en-us"><head><title>Day 1 - Advent of Code 2024</title></head><body>
+<main>
+<article><p>%s</p></article>
+</main>
+</body></html>`
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		article string
+		outcome history.Outcome
+		wait    time.Duration
+	}{
+		{
+			name:    "correct",
+			article: `That's the right answer!  You are <span class="day-success">one gold star</span> closer to finding the Chief Historian.`,
+			outcome: history.Correct,
+		},
+		{
+			name:    "too high",
+			article: `That's not the right answer; your answer is too high.  If you're stuck, make sure you're using the full input data.`,
+			outcome: history.TooHigh,
+		},
+		{
+			name:    "too low",
+			article: `That's not the right answer; your answer is too low.`,
+			outcome: history.TooLow,
+		},
+		{
+			name:    "wrong",
+			article: `That's not the right answer.  If you're stuck, make sure you're using the full input data.`,
+			outcome: history.Wrong,
+		},
+		{
+			name:    "wait seconds",
+			article: `You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 34s left to wait. [<a href="/2024/day/1">Return to Day 1</a>]`,
+			outcome: history.Wait,
+			wait:    34 * time.Second,
+		},
+		{
+			name:    "wait minutes",
+			article: `You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 4m 2s left to wait.`,
+			outcome: history.Wait,
+			wait:    4*time.Minute + 2*time.Second,
+		},
+		{
+			name:    "already solved",
+			article: `You don't seem to be solving the right level.  Did you already complete it? [<a href="/2024/day/1">Return to Day 1</a>]`,
+			outcome: history.AlreadySolved,
+		},
+		{
+			name:    "unknown",
+			article: `Something else entirely.`,
+			outcome: history.Unknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := ParseResponse([]byte(fmt.Sprintf(page, tt.article)))
+			if resp.Outcome != tt.outcome || resp.Wait != tt.wait {
+				t.Errorf("ParseResponse() = %+v, want %s and wait %v", resp, tt.outcome, tt.wait)
+			}
+		})
+	}
+}
+
+func TestParseResponse_Message(t *testing.T) {
+	resp := ParseResponse([]byte(fmt.Sprintf(page, "That&apos;s the <em>right</em>\n  answer!")))
+	if resp.Message != "That's the right answer!" {
+		t.Errorf("Message = %q", resp.Message)
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/2024/day/3/answer" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.FormValue("level") != "2" || r.FormValue("answer") != "161" {
+			t.Errorf("unexpected form %v", r.Form)
+		}
+		w.Write([]byte(fmt.Sprintf(page, "That's the right answer!")))
+	}))
+	defer server.Close()
+
+	resp, err := newClient(t, server.URL).Submit(context.Background(), 3, 2, 161)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Outcome != history.Correct {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseDay accepts "2", "02" and "day02".
+func parseDay(s string) (int, error) {
+	day, err := strconv.Atoi(strings.TrimPrefix(s, "day"))
+	if err != nil || day < 1 || day > 25 {
+		return 0, fmt.Errorf("invalid day %q", s)
+	}
+	return day, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"adventofcode2024/client"
+	"adventofcode2024/puzzle"
+)
+
+func runDescribe(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("describe", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: aoc describe [flags] <day>")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Saves the puzzle description as dayNN/README.md and its example blocks as dayNN/testdata/exampleN.txt.")
+		fmt.Fprintln(stderr, "Example files that already have content are left alone.")
+		fs.PrintDefaults()
+	}
+	root := fs.String("root", ".", "repository root")
+	cached := fs.Bool("cached", false, "use the cached page instead of downloading it again")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	number, err := parseDay(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(*root, dayDir(number))
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("%w; create the day with aoc new %d", err, number)
+	}
+
+	c, err := client.FromEnv()
+	if err != nil {
+		return err
+	}
+	page, err := c.Page(ctx, number, !*cached)
+	if err != nil {
+		return err
+	}
+	p, err := puzzle.Parse(page)
+	if err != nil {
+		return err
+	}
+
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte(p.Markdown(c.PageURL(number))), 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "wrote %s\n", readme)
+
+	for i, example := range p.Examples() {
+		filename := filepath.Join(dir, "testdata", fmt.Sprintf("example%d.txt", i+1))
+		written, err := writeExample(filename, example)
+		if err != nil {
+			return err
+		}
+		if written {
+			fmt.Fprintf(stdout, "wrote %s\n", filename)
+		}
+	}
+	return nil
+}
+
+// writeExample writes content to filename unless the file already has
+// something else in it, which is likely to have been edited by hand.
+func writeExample(filename, content string) (bool, error) {
+	existing, err := os.ReadFile(filename)
+	if err == nil && len(existing) > 0 {
+		return false, nil
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(filename, []byte(content), 0644)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	page, err := os.ReadFile("../../puzzle/testdata/day.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2024/day/4" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(page)
+	}))
+	defer server.Close()
+	t.Setenv("AOC_BASE_URL", server.URL)
+	t.Setenv("AOC_SESSION", "")
+	t.Setenv("AOC_CACHE_DIR", t.TempDir())
+	t.Setenv("AOC_MIN_INTERVAL", "0s")
+
+	root := t.TempDir()
+	if _, _, err := runAoc(t, "describe", "-root", root, "4"); err == nil {
+		t.Error("expected an error for a day that does not exist")
+	}
+
+	if _, _, err := runAoc(t, "new", "-root", root, "4"); err != nil {
+		t.Fatal(err)
+	}
+	edited := filepath.Join(root, "day04", "testdata", "example2.txt")
+	if err := os.WriteFile(edited, []byte("edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, err := runAoc(t, "describe", "-root", root, "4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Count(stdout, "wrote") != 2 {
+		t.Errorf("expected the README and one example to be written, got %q", stdout)
+	}
+
+	expected := map[string]string{
+		"example1.txt": "3   4\n4   3\n2   5\n",
+		"example2.txt": "edited\n",
+	}
+	for name, content := range expected {
+		actual, err := os.ReadFile(filepath.Join(root, "day04", "testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != content {
+			t.Errorf("%s = %q, want %q", name, actual, content)
+		}
+	}
+
+	readme, err := os.ReadFile(filepath.Join(root, "day04", "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(readme), "## Day 1: Sample Sorting\n") || !strings.Contains(string(readme), "("+server.URL+"/2024/day/1/input)") {
+		t.Errorf("unexpected README:\n%s", readme)
+	}
+
+	server.Close()
+	if _, _, err := runAoc(t, "describe", "-root", root, "-cached", "4"); err != nil {
+		t.Errorf("expected the cached page to be used, got %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"adventofcode2024/client"
+	"adventofcode2024/puzzle"
+	"adventofcode2024/runner"
+)
+
+func runExamples(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("examples", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: aoc examples [flags] <day>")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Turns the examples of a puzzle description into golden test cases in dayNN/testdata:")
+		fmt.Fprintln(stderr, "exampleN.txt, exampleN.expected and, for examples that cover one part, exampleN.args.")
+		fmt.Fprintln(stderr, "It uses the page saved by aoc describe. Answers it wrote for fewer parts are brought up to date,")
+		fmt.Fprintln(stderr, "and other files that already have content are left alone unless -force is given.")
+		fs.PrintDefaults()
+	}
+	root := fs.String("root", ".", "repository root")
+	refresh := fs.Bool("refresh", false, "download the page again instead of using the saved one, e.g. once part two is unlocked")
+	force := fs.Bool("force", false, "overwrite example files that differ, even if they were edited by hand")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	number, err := parseDay(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(*root, dayDir(number))
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("%w; create the day with aoc new %d", err, number)
+	}
+
+	c, err := client.FromEnv()
+	if err != nil {
+		return err
+	}
+	page, err := c.Page(ctx, number, *refresh)
+	if err != nil {
+		return err
+	}
+	p, err := puzzle.Parse(page)
+	if err != nil {
+		return err
+	}
+	cases := p.Cases()
+	if len(cases) == 0 {
+		return errors.New("found no example with an expected answer")
+	}
+
+	for _, tc := range cases {
+		testdata := filepath.Join(dir, "testdata")
+		filename := filepath.Join(testdata, tc.Name+".txt")
+		written, err := writeInput(filename, tc.Input, *force)
+		if err != nil {
+			return err
+		}
+		if written {
+			fmt.Fprintf(stdout, "wrote %s\n", filename)
+		} else if !sameContent(filename, tc.Input) {
+			fmt.Fprintf(stderr, "kept %s, which differs from the example; use -force to overwrite it\n", filename)
+		}
+		if err := writeAnswers(filepath.Join(testdata, tc.Name), tc.Answers, *force, stdout, stderr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeInput(filename, content string, force bool) (bool, error) {
+	if !force {
+		return writeExample(filename, content)
+	}
+	if sameContent(filename, content) {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(filename, []byte(content), 0644)
+}
+
+func sameContent(filename, content string) bool {
+	existing, err := os.ReadFile(filename)
+	return err == nil && string(existing) == content
+}
+
+// writeAnswers writes base.expected and, for answers that cover a single
+// part, base.args. Files that are empty, missing or hold what this command
+// writes for some of the parts, as it does before part two is unlocked,
+// are replaced; anything else is only replaced with force.
+func writeAnswers(base string, answers map[int]int, force bool, stdout, stderr io.Writer) error {
+	read := func(filename string) (string, error) {
+		content, err := os.ReadFile(filename)
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return string(content), err
+	}
+	expectedFile, argsFile := base+".expected", base+".args"
+	currentExpected, err := read(expectedFile)
+	if err != nil {
+		return err
+	}
+	currentArgs, err := read(argsFile)
+	if err != nil {
+		return err
+	}
+
+	wantExpected, wantArgs := expected(answers), args(answers)
+	if currentExpected == wantExpected && currentArgs == wantArgs {
+		return nil
+	}
+	generated := currentExpected == "" && currentArgs == ""
+	for _, subset := range subsets(answers) {
+		if currentExpected == expected(subset) && currentArgs == args(subset) {
+			generated = true
+		}
+	}
+	if !generated && !force {
+		fmt.Fprintf(stderr, "kept %s, which differs from the answers %s; use -force to overwrite it\n", expectedFile, strings.TrimSpace(wantExpected))
+		return nil
+	}
+
+	if err := os.WriteFile(expectedFile, []byte(wantExpected), 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "wrote %s\n", expectedFile)
+	switch {
+	case wantArgs != "":
+		if err := os.WriteFile(argsFile, []byte(wantArgs), 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "wrote %s\n", argsFile)
+	case currentArgs != "":
+		if err := os.Remove(argsFile); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "removed %s\n", argsFile)
+	}
+	return nil
+}
+
+// args is the content of the .args file for answers: a -part flag when
+// they cover a single part, and nothing otherwise.
+func args(answers map[int]int) string {
+	if len(answers) != 1 {
+		return ""
+	}
+	for part := range answers {
+		return fmt.Sprintf("-part %d\n", part)
+	}
+	return ""
+}
+
+// subsets returns answers restricted to each single part, which is what
+// earlier runs wrote when fewer parts were known.
+func subsets(answers map[int]int) []map[int]int {
+	var result []map[int]int
+	for part, answer := range answers {
+		result = append(result, map[int]int{part: answer})
+	}
+	return result
+}
+
+// expected is the runner's text output for answers.
+func expected(answers map[int]int) string {
+	parts := make([]int, 0, len(answers))
+	for part := range answers {
+		parts = append(parts, part)
+	}
+	slices.Sort(parts)
+
+	var sb strings.Builder
+	for _, part := range parts {
+		sb.WriteString(runner.TextResult(part, answers[part]))
+	}
+	return sb.String()
+}
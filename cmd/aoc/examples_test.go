@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExamples(t *testing.T) {
+	page, err := os.ReadFile("../../puzzle/testdata/day.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(page)
+	}))
+	defer server.Close()
+	t.Setenv("AOC_BASE_URL", server.URL)
+	t.Setenv("AOC_SESSION", "")
+	t.Setenv("AOC_CACHE_DIR", t.TempDir())
+	t.Setenv("AOC_MIN_INTERVAL", "0s")
+
+	root := t.TempDir()
+	if _, _, err := runAoc(t, "new", "-root", root, "5"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := runAoc(t, "describe", "-root", root, "5"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := runAoc(t, "examples", "-root", root, "5"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected examples to reuse the page saved by describe, got %d requests", requests)
+	}
+
+	expected := map[string]string{
+		"example1.txt":      "3   4\n4   3\n2   5\n",
+		"example1.args":     "-part 1\n",
+		"example1.expected": "Part1 result:  11\n",
+		"example2.txt":      "3   4\n4   3\n",
+		"example2.args":     "-part 2\n",
+		"example2.expected": "Part2 result:  31\n",
+	}
+	for name, content := range expected {
+		actual, err := os.ReadFile(filepath.Join(root, "day05", "testdata", name))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if string(actual) != content {
+			t.Errorf("%s = %q, want %q", name, actual, content)
+		}
+	}
+}
+
+func TestExamples_PartTwo(t *testing.T) {
+	part1 := `<article class="day-desc"><h2>--- Day 5 ---</h2><p>For example:</p>` +
+		`<pre><code>3   4\n4   3\n</code></pre><p>The total is <code><em>11</em></code>.</p></article>`
+	part2 := `<article class="day-desc"><h2 id="part2">--- Part Two ---</h2>` +
+		`<p>For the same example, the score is <code><em>31</em></code>.</p></article>`
+	page := "<main>" + part1 + "</main>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+	t.Setenv("AOC_BASE_URL", server.URL)
+	t.Setenv("AOC_SESSION", "")
+	t.Setenv("AOC_CACHE_DIR", t.TempDir())
+	t.Setenv("AOC_MIN_INTERVAL", "0s")
+
+	root := t.TempDir()
+	if _, _, err := runAoc(t, "new", "-root", root, "5"); err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(root, "day05", "testdata")
+	check := func(name, content string) {
+		t.Helper()
+		actual, err := os.ReadFile(filepath.Join(testdata, name))
+		if content == "" {
+			if !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("%s should not exist, got %q, %v", name, actual, err)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != content {
+			t.Errorf("%s = %q, want %q", name, actual, content)
+		}
+	}
+
+	if _, _, err := runAoc(t, "examples", "-root", root, "5"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	check("example1.args", "-part 1\n")
+	check("example1.expected", "Part1 result:  11\n")
+
+	// Part two is unlocked, but the saved page only has part one.
+	page = "<main>" + part1 + part2 + "</main>"
+	if _, _, err := runAoc(t, "examples", "-root", root, "5"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	check("example1.expected", "Part1 result:  11\n")
+
+	stdout, _, err := runAoc(t, "examples", "-refresh", "-root", root, "5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	check("example1.args", "")
+	check("example1.expected", "Part1 result:  11\nPart2 result:  31\n")
+	if !strings.Contains(stdout, "removed ") {
+		t.Errorf("output %q should mention the removed args file", stdout)
+	}
+
+	// Answers edited by hand are reported and kept, unless forced.
+	edited := filepath.Join(testdata, "example1.expected")
+	if err := os.WriteFile(edited, []byte("Part1 result:  12\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, stderr, err := runAoc(t, "examples", "-root", root, "5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	check("example1.expected", "Part1 result:  12\n")
+	if !strings.Contains(stderr, "kept "+edited) || !strings.Contains(stderr, "-force") {
+		t.Errorf("stderr %q should report the kept file", stderr)
+	}
+	if _, _, err := runAoc(t, "examples", "-force", "-root", root, "5"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	check("example1.expected", "Part1 result:  11\nPart2 result:  31\n")
+}
+
+func TestExpected(t *testing.T) {
+	if got := expected(map[int]int{2: 31, 1: 11}); got != "Part1 result:  11\nPart2 result:  31\n" {
+		t.Errorf("expected() = %q", got)
+	}
+}
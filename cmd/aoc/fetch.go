@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"adventofcode2024/client"
+	"adventofcode2024/input"
+)
+
+func runFetch(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("fetch", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: aoc fetch [flags] <day>")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Downloads the puzzle input, or takes it from the cache if it was already downloaded.")
+		fmt.Fprintln(stderr, "The session token is read from AOC_SESSION or the aoc/session file in the user config directory.")
+		fs.PrintDefaults()
+	}
+	root := fs.String("root", ".", "repository root")
+	output := fs.String("o", "", `write to this file instead of dayNN/input, or "-" for stdout`)
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	number, err := parseDay(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	c, err := client.FromEnv()
+	if err != nil {
+		return err
+	}
+	content, err := c.Input(ctx, number)
+	if err != nil {
+		return err
+	}
+
+	if *output == "-" {
+		_, err := stdout.Write(content)
+		return err
+	}
+	filename := *output
+	if filename == "" {
+		filename = filepath.Join(*root, dayDir(number), "input")
+	}
+	if err := input.Replace(filename, func(w io.Writer) error {
+		_, err := w.Write(content)
+		return err
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(stderr, "wrote %s\n", filename)
+	return nil
+}
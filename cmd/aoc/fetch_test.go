@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2024/day/2/input" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("1 2 3\n"))
+	}))
+	defer server.Close()
+	t.Setenv("AOC_BASE_URL", server.URL)
+	t.Setenv("AOC_SESSION", "secret")
+	t.Setenv("AOC_CACHE_DIR", t.TempDir())
+	t.Setenv("AOC_MIN_INTERVAL", "0s")
+
+	output := filepath.Join(t.TempDir(), "input")
+	if _, _, err := runAoc(t, "fetch", "-o", output, "2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "1 2 3\n" {
+		t.Errorf("unexpected input %q", content)
+	}
+
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "day02"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := runAoc(t, "fetch", "-root", root, "2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, err = os.ReadFile(filepath.Join(root, "day02", "input"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "1 2 3\n" {
+		t.Errorf("unexpected input %q under -root", content)
+	}
+
+	stdout, _, err := runAoc(t, "fetch", "-o", "-", "day02")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "1 2 3\n" {
+		t.Errorf("unexpected output %q", stdout)
+	}
+
+	if _, _, err := runAoc(t, "fetch", "-o", "-", "3"); err == nil {
+		t.Error("expected an error for a missing input")
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"adventofcode2024/gen"
+	"adventofcode2024/input"
+)
+
+func runGen(_ context.Context, args []string, stdout, stderr io.Writer) error {
+	day01 := gen.DefaultDay01Options
+	day02 := gen.DefaultDay02Options
+
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: aoc gen [flags] <day>")
+		fs.PrintDefaults()
+	}
+	seed := fs.Uint64("seed", 1, "random seed")
+	size := fs.Int("n", 1000, "number of lines to generate")
+	output := fs.String("o", "", "write to this file instead of stdout")
+	minValue := fs.Int("min", 0, "smallest value (defaults to the day's own)")
+	maxValue := fs.Int("max", 0, "largest value (defaults to the day's own)")
+	fs.Float64Var(&day01.DuplicateRate, "dup", day01.DuplicateRate, "day 1: fraction of right values copied from the left list")
+	fs.IntVar(&day02.MinLevels, "min-levels", day02.MinLevels, "day 2: fewest levels per report")
+	fs.IntVar(&day02.MaxLevels, "max-levels", day02.MaxLevels, "day 2: most levels per report")
+	fs.Float64Var(&day02.SafeRate, "safe", day02.SafeRate, "day 2: fraction of safe reports")
+	fs.Float64Var(&day02.DampenableRate, "dampenable", day02.DampenableRate, "day 2: fraction of reports made safe by the dampener")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	// Only flags given on the command line override the day's defaults, so
+	// that an explicit 0 is kept.
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	setIfGiven := func(dst *int, name string, value int) {
+		if given[name] {
+			*dst = value
+		}
+	}
+
+	day, err := parseDay(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	r := gen.NewRand(*seed)
+	var write func(w io.Writer) error
+	switch day {
+	case 1:
+		day01.Lines = *size
+		setIfGiven(&day01.Min, "min", *minValue)
+		setIfGiven(&day01.Max, "max", *maxValue)
+		pairs, err := gen.Day01(r, day01)
+		if err != nil {
+			return err
+		}
+		write = func(w io.Writer) error { return gen.WriteDay01(w, pairs) }
+	case 2:
+		day02.Reports = *size
+		setIfGiven(&day02.Min, "min", *minValue)
+		setIfGiven(&day02.Max, "max", *maxValue)
+		reports, err := gen.Day02(r, day02)
+		if err != nil {
+			return err
+		}
+		write = func(w io.Writer) error { return gen.WriteDay02(w, reports) }
+	default:
+		return fmt.Errorf("no generator for day %d", day)
+	}
+
+	if *output == "" {
+		return write(stdout)
+	}
+	// The file may be a day's input that is mapped by a running solver.
+	return input.Replace(*output, write)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGen(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		lines  int
+		fields int
+	}{
+		{name: "day 1", args: []string{"gen", "-n", "20", "1"}, lines: 20, fields: 2},
+		{name: "day 2", args: []string{"gen", "-n", "15", "-min-levels", "6", "-max-levels", "6", "day02"}, lines: 15, fields: 6},
+		{name: "custom range", args: []string{"gen", "-n", "5", "-min", "1", "-max", "9", "1"}, lines: 5, fields: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, _, err := runAoc(t, tt.args...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+			if len(lines) != tt.lines {
+				t.Fatalf("got %d lines, expected %d", len(lines), tt.lines)
+			}
+			for _, line := range lines {
+				if len(strings.Fields(line)) != tt.fields {
+					t.Errorf("line %q does not have %d fields", line, tt.fields)
+				}
+			}
+		})
+	}
+}
+
+func TestGen_ZeroRange(t *testing.T) {
+	stdout, _, err := runAoc(t, "gen", "-n", "50", "-min", "0", "-max", "0", "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(stdout, "\n"), "\n") {
+		if line != "0   0" {
+			t.Fatalf("line %q should only hold zeros", line)
+		}
+	}
+}
+
+func TestGen_Output(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "input")
+	if _, _, err := runAoc(t, "gen", "-seed", "9", "-o", output, "2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, err := runAoc(t, "gen", "-seed", "9", "2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != string(first) {
+		t.Error("the same seed should produce the same file")
+	}
+}
+
+func TestGen_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing day", args: []string{"gen"}},
+		{name: "bad flag", args: []string{"gen", "-nope", "1"}},
+		{name: "invalid day", args: []string{"gen", "x"}},
+		{name: "day without generator", args: []string{"gen", "3"}},
+		{name: "invalid options", args: []string{"gen", "-safe", "2", "2"}},
+		{name: "negative levels", args: []string{"gen", "-min", "-5", "-max", "10", "2"}},
+		{name: "unwritable output", args: []string{"gen", "-o", filepath.Join(t.TempDir(), "missing", "input"), "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := runAoc(t, tt.args...); err == nil {
+				t.Errorf("aoc %v should fail", tt.args)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+
+	"adventofcode2024/history"
+)
+
+func runHistory(_ context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: aoc history [day]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Lists the submitted answers and what is known about each part.")
+	}
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return errUsage
+	}
+	only := 0
+	if fs.NArg() == 1 {
+		var err error
+		if only, err = parseDay(fs.Arg(0)); err != nil {
+			return err
+		}
+	}
+
+	h, err := history.LoadDefault()
+	if err != nil {
+		return err
+	}
+	type key struct{ day, part int }
+	var parts []key
+	for _, a := range h.Attempts {
+		k := key{a.Day, a.Part}
+		if (only == 0 || a.Day == only) && !slices.Contains(parts, k) {
+			parts = append(parts, k)
+		}
+	}
+	slices.SortFunc(parts, func(a, b key) int {
+		if a.day != b.day {
+			return a.day - b.day
+		}
+		return a.part - b.part
+	})
+
+	for _, k := range parts {
+		fmt.Fprintf(stdout, "day %d part %d: %s\n", k.day, k.part, summary(h, k.day, k.part))
+		for _, a := range h.For(k.day, k.part) {
+			fmt.Fprintf(stdout, "  %s  %d  %s\n", a.Time.Local().Format("2006-01-02 15:04"), a.Answer, a.Outcome)
+		}
+	}
+	return nil
+}
+
+func summary(h *history.History, day, part int) string {
+	if answer, ok := h.Solution(day, part); ok {
+		return fmt.Sprintf("solved with %d", answer)
+	}
+	low, high, hasLow, hasHigh := h.Bounds(day, part)
+	switch {
+	case hasLow && hasHigh:
+		return fmt.Sprintf("unsolved, between %d and %d", low, high)
+	case hasLow:
+		return fmt.Sprintf("unsolved, above %d", low)
+	case hasHigh:
+		return fmt.Sprintf("unsolved, below %d", high)
+	}
+	return "unsolved"
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"adventofcode2024/history"
+)
+
+func TestHistory(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "history.json")
+	t.Setenv("AOC_HISTORY", filename)
+
+	h := &history.History{}
+	at := time.Date(2024, 12, 2, 6, 1, 0, 0, time.UTC)
+	h.Record(history.Attempt{Day: 2, Part: 2, Answer: 100, Outcome: history.TooHigh, Time: at})
+	h.Record(history.Attempt{Day: 1, Part: 1, Answer: 11, Outcome: history.Correct, Time: at})
+	h.Record(history.Attempt{Day: 2, Part: 2, Answer: 10, Outcome: history.TooLow, Time: at})
+	if err := h.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name: "all days",
+			args: []string{"history"},
+			expected: "day 1 part 1: solved with 11\n" +
+				"  " + at.Local().Format("2006-01-02 15:04") + "  11  correct\n" +
+				"day 2 part 2: unsolved, between 10 and 100\n" +
+				"  " + at.Local().Format("2006-01-02 15:04") + "  100  too high\n" +
+				"  " + at.Local().Format("2006-01-02 15:04") + "  10  too low\n",
+		},
+		{
+			name: "one day",
+			args: []string{"history", "1"},
+			expected: "day 1 part 1: solved with 11\n" +
+				"  " + at.Local().Format("2006-01-02 15:04") + "  11  correct\n",
+		},
+		{name: "no attempts", args: []string{"history", "5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, _, err := runAoc(t, tt.args...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stdout != tt.expected {
+				t.Errorf("Expected output:\n%s\nGot:\n%s", tt.expected, stdout)
+			}
+		})
+	}
+}
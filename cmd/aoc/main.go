@@ -0,0 +1,70 @@
+// Command aoc bundles the tooling around the day packages.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string, stdout, stderr io.Writer) error
+}
+
+var commands = []command{
+	{name: "describe", summary: "save a puzzle description and its examples", run: runDescribe},
+	{name: "examples", summary: "turn a puzzle's examples into golden test cases", run: runExamples},
+	{name: "fetch", summary: "download a puzzle input", run: runFetch},
+	{name: "gen", summary: "generate a random puzzle input", run: runGen},
+	{name: "history", summary: "list submitted answers", run: runHistory},
+	{name: "minimize", summary: "shrink an input while a command keeps failing on it", run: runMinimize},
+	{name: "new", summary: "create the skeleton of a new day", run: runNew},
+	{name: "run", summary: "run a registered day", run: runRun},
+	{name: "submit", summary: "submit an answer", run: runSubmit},
+	{name: "watch", summary: "rebuild, test and run a day on every change", run: runWatch},
+}
+
+var errUsage = errors.New("usage")
+
+func main() {
+	log.SetFlags(0)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		usage(stderr)
+		return errUsage
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(ctx, args[1:], stdout, stderr)
+		}
+	}
+	fmt.Fprintf(stderr, "aoc: unknown command %q\n", args[0])
+	usage(stderr)
+	return errUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: aoc <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func runAoc(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), args, &stdout, &stderr)
+	return stdout.String(), stderr.String(), err
+}
+
+func TestRun_Usage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no command", args: nil},
+		{name: "unknown command", args: []string{"nope"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, stderr, err := runAoc(t, tt.args...)
+			if !errors.Is(err, errUsage) {
+				t.Errorf("expected usage error, got %v", err)
+			}
+			if !strings.Contains(stderr, "Usage: aoc") {
+				t.Errorf("expected usage on stderr, got %q", stderr)
+			}
+		})
+	}
+}
+
+func TestParseDay(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    int
+		expectError bool
+	}{
+		{input: "1", expected: 1},
+		{input: "02", expected: 2},
+		{input: "day25", expected: 25},
+		{input: "0", expectError: true},
+		{input: "26", expectError: true},
+		{input: "dayx", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			day, err := parseDay(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("parseDay(%q) should error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if day != tt.expected {
+				t.Errorf("parseDay(%q) = %d, want %d", tt.input, day, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"adventofcode2024/input"
+	"adventofcode2024/minimize"
+)
+
+func runMinimize(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("minimize", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: aoc minimize [flags] <input> <command> [arguments]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Shrinks <input> to the fewest lines for which <command> still exits 0.")
+		fmt.Fprintln(stderr, "The candidate file replaces a {} argument, or is appended if there is none.")
+		fs.PrintDefaults()
+	}
+	output := fs.String("o", "", "write the minimized input to this file instead of stdout")
+	verbose := fs.Bool("v", false, "log every candidate that is tried")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return errUsage
+	}
+
+	content, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "aoc-minimize")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	candidate := filepath.Join(dir, filepath.Base(fs.Arg(0)))
+
+	attempts := 0
+	test := func(text string) (bool, error) {
+		attempts++
+		if err := os.WriteFile(candidate, []byte(text), 0644); err != nil {
+			return false, err
+		}
+		ok, err := interesting(ctx, fs.Args()[1:], candidate)
+		if *verbose {
+			fmt.Fprintf(stderr, "attempt %d: %d lines, interesting=%t\n", attempts, strings.Count(text, "\n"), ok)
+		}
+		return ok, err
+	}
+
+	if ok, err := test(string(content)); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("the command does not exit 0 on the original input")
+	}
+
+	result, err := minimize.Lines(strings.NewReader(string(content)), test)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stderr, "minimized to %d lines in %d attempts\n", strings.Count(result, "\n"), attempts)
+
+	if *output != "" {
+		return input.Replace(*output, func(w io.Writer) error {
+			_, err := io.WriteString(w, result)
+			return err
+		})
+	}
+	_, err = io.WriteString(stdout, result)
+	return err
+}
+
+// interesting runs the predicate command on the candidate file. Exiting 0
+// means the candidate still shows the problem; any other exit status means
+// it does not. Failing to start the command at all is an error.
+func interesting(ctx context.Context, command []string, candidate string) (bool, error) {
+	args := make([]string, 0, len(command))
+	replaced := false
+	for _, arg := range command[1:] {
+		if strings.Contains(arg, "{}") {
+			arg = strings.ReplaceAll(arg, "{}", candidate)
+			replaced = true
+		}
+		args = append(args, arg)
+	}
+	if !replaced {
+		args = append(args, candidate)
+	}
+
+	err := exec.CommandContext(ctx, command[0], args...).Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMinimize(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input")
+	content := "7 6 4 2 1\n1 2 7 8 9\n9 7 6 2 1\n1 3 2 4 5\n8 6 4 4 1\n1 3 6 7 9\n"
+	if err := os.WriteFile(input, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "appended path", args: []string{"grep", "-q", "4 4"}},
+		{name: "placeholder", args: []string{"grep", "-q", "-e", "4 4", "{}"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"minimize", input}, tt.args...)
+			stdout, stderr, err := runAoc(t, args...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stdout != "8 6 4 4 1\n" {
+				t.Errorf("minimized to %q", stdout)
+			}
+			if !strings.Contains(stderr, "minimized to 1 lines") {
+				t.Errorf("unexpected stderr %q", stderr)
+			}
+		})
+	}
+}
+
+func TestMinimize_Output(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input")
+	output := filepath.Join(dir, "minimal")
+	if err := os.WriteFile(input, []byte("a\nb\nc\nd\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := runAoc(t, "minimize", "-o", output, "-v", input, "grep", "-q", "c"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "c\n" {
+		t.Errorf("minimized to %q", content)
+	}
+}
+
+func TestMinimize_Errors(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input")
+	if err := os.WriteFile(input, []byte("a\nb\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing command", args: []string{"minimize", input}},
+		{name: "bad flag", args: []string{"minimize", "-nope", input, "true"}},
+		{name: "missing input", args: []string{"minimize", filepath.Join(dir, "nope"), "true"}},
+		{name: "not failing on original", args: []string{"minimize", input, "grep", "-q", "zzz"}},
+		{name: "command not found", args: []string{"minimize", input, filepath.Join(dir, "no-such-command")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := runAoc(t, tt.args...); err == nil {
+				t.Errorf("aoc %v should fail", tt.args)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// registryFile is regenerated from the dayNN directories after every
+// aoc new, so that aoc run knows which days exist.
+const registryFile = "cmd/aoc/days.go"
+
+var registry = template.Must(template.New("days.go").Parse(`// Code generated by aoc new; DO NOT EDIT.
+
+package main
+
+// days lists the day packages, each in its own dayNN directory.
+var days = []int{ {{- range $i, $d := .}}{{if $i}}, {{end}}{{$d}}{{end -}} }
+`))
+
+func runNew(_ context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("new", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: aoc new [flags] <day>")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Creates the dayNN package skeleton and registers it with aoc run.")
+		fs.PrintDefaults()
+	}
+	root := fs.String("root", ".", "repository root")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	number, err := parseDay(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(*root, dayDir(number))
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("%s already exists", dir)
+	}
+	if err := scaffold(dir, number); err != nil {
+		return err
+	}
+	if err := writeRegistry(*root); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "created %s\n", dir)
+	return nil
+}
+
+func dayDir(number int) string {
+	return fmt.Sprintf("day%02d", number)
+}
+
+// scaffold renders every file under templates into dir. The .tmpl suffix
+// is dropped and "dayNN" in a file name becomes the day's directory name.
+func scaffold(dir string, number int) error {
+	data := struct{ Number int }{number}
+	return fs.WalkDir(templates, "templates", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		text, err := fs.ReadFile(templates, name)
+		if err != nil {
+			return err
+		}
+		tmpl, err := template.New(path.Base(name)).Parse(string(text))
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return err
+		}
+
+		target := strings.TrimSuffix(strings.TrimPrefix(name, "templates/"), ".tmpl")
+		target = strings.ReplaceAll(target, "dayNN", dayDir(number))
+		content := buf.Bytes()
+		if strings.HasSuffix(target, ".go") {
+			if content, err = format.Source(content); err != nil {
+				return fmt.Errorf("%s: %w", target, err)
+			}
+		}
+
+		target = filepath.Join(dir, filepath.FromSlash(target))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(target, content, 0644)
+	})
+}
+
+// writeRegistry lists the dayNN directories under root into registryFile.
+func writeRegistry(root string) error {
+	content, err := renderRegistry(root)
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(root, filepath.FromSlash(registryFile))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(target, content, 0644)
+}
+
+func renderRegistry(root string) ([]byte, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	var numbers []int
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "day") {
+			continue
+		}
+		if number, err := parseDay(entry.Name()); err == nil && entry.Name() == dayDir(number) {
+			numbers = append(numbers, number)
+		}
+	}
+	slices.Sort(numbers)
+
+	var buf bytes.Buffer
+	if err := registry.Execute(&buf, numbers); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
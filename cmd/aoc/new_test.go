@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "day01"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, err := runAoc(t, "new", "-root", root, "7")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "day07") {
+		t.Errorf("unexpected output %q", stdout)
+	}
+
+	for _, name := range []string{"main.go", "part1.go", "part2.go", "day07_test.go"} {
+		if _, err := parser.ParseFile(token.NewFileSet(), filepath.Join(root, "day07", name), nil, 0); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	main, err := os.ReadFile(filepath.Join(root, "day07", "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(main), "runner.Day{Number: 7,") {
+		t.Errorf("main.go does not declare day 7:\n%s", main)
+	}
+	if _, err := os.Stat(filepath.Join(root, "day07", "testdata", "example1.txt")); err != nil {
+		t.Error(err)
+	}
+
+	registry, err := os.ReadFile(filepath.Join(root, registryFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(registry), "var days = []int{1, 7}") {
+		t.Errorf("unexpected registry:\n%s", registry)
+	}
+
+	if _, _, err := runAoc(t, "new", "-root", root, "7"); err == nil {
+		t.Error("expected an error for an existing day")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	expected, err := renderRegistry("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := os.ReadFile("days.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("days.go is out of date with the day directories, expected:\n%s", expected)
+	}
+}
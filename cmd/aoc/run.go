@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+)
+
+func runRun(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: aoc run [flags] <day> [day flags]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Runs a registered day from its own directory; the day flags are passed on.")
+		fs.PrintDefaults()
+	}
+	root := fs.String("root", ".", "repository root")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return errUsage
+	}
+	number, err := registeredDay(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cmd := dayCommand(ctx, *root, number, fs.Args()[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// registeredDay parses a day like parseDay and checks that it is in days.
+func registeredDay(s string) (int, error) {
+	number, err := parseDay(s)
+	if err != nil {
+		return 0, err
+	}
+	if !slices.Contains(days, number) {
+		return 0, fmt.Errorf("day %d is not registered; create it with aoc new %d", number, number)
+	}
+	return number, nil
+}
+
+// dayCommand runs a day with go run from its directory, where it finds its
+// default input.
+func dayCommand(ctx context.Context, root string, number int, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "go", append([]string{"run", "."}, args...)...)
+	cmd.Dir = filepath.Join(root, dayDir(number))
+	return cmd
+}
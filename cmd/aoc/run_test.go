@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunDay(t *testing.T) {
+	stdout, _, err := runAoc(t, "run", "-root", "../..", "1", "-input", "testdata/example1.txt", "-part", "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "Part1 result:  11\n" {
+		t.Errorf("unexpected output %q", stdout)
+	}
+}
+
+func TestRunDay_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		message string
+	}{
+		{name: "no day", args: []string{"run"}, message: "usage"},
+		{name: "invalid day", args: []string{"run", "x"}, message: "invalid day"},
+		{name: "unregistered day", args: []string{"run", "25"}, message: "aoc new 25"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := runAoc(t, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("expected an error containing %q, got %v", tt.message, err)
+			}
+		})
+	}
+}
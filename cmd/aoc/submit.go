@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"adventofcode2024/client"
+	"adventofcode2024/history"
+	"adventofcode2024/runner"
+)
+
+func runSubmit(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("submit", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: aoc submit [flags] <day> <part> [answer]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Submits an answer, computing it by running the day when it is not given.")
+		fmt.Fprintln(stderr, "Answers already rejected, or outside the bounds set by earlier too high and too low answers, are not sent.")
+		fs.PrintDefaults()
+	}
+	root := fs.String("root", ".", "repository root")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() != 2 && fs.NArg() != 3 {
+		fs.Usage()
+		return errUsage
+	}
+	number, err := registeredDay(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	part, err := strconv.Atoi(fs.Arg(1))
+	if err != nil || part < 1 || part > 2 {
+		return fmt.Errorf("invalid part %q", fs.Arg(1))
+	}
+
+	var answer int
+	var inputHash string
+	if fs.NArg() == 3 {
+		if answer, err = strconv.Atoi(fs.Arg(2)); err != nil {
+			return fmt.Errorf("invalid answer %q", fs.Arg(2))
+		}
+		inputHash = hashFile(filepath.Join(*root, dayDir(number), "input"))
+	} else {
+		res, err := solve(ctx, *root, number, part, stderr)
+		if err != nil {
+			return err
+		}
+		answer, inputHash = res.Answer, res.InputHash
+	}
+
+	path, err := history.DefaultPath()
+	if err != nil {
+		return err
+	}
+	h, err := history.Load(path)
+	if err != nil {
+		return err
+	}
+	if err := h.Check(number, part, answer); err != nil {
+		return fmt.Errorf("not submitting: %w", err)
+	}
+
+	c, err := client.FromEnv()
+	if err != nil {
+		return err
+	}
+	resp, err := c.Submit(ctx, number, part, answer)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "day %d part %d: %d is %s\n", number, part, answer, resp.Outcome)
+	fmt.Fprintln(stdout, resp.Message)
+
+	switch resp.Outcome {
+	case history.Correct, history.TooHigh, history.TooLow, history.Wrong:
+		h.Record(history.Attempt{
+			Day:       number,
+			Part:      part,
+			Answer:    answer,
+			Outcome:   resp.Outcome,
+			InputHash: inputHash,
+			Time:      time.Now(),
+		})
+		if err := h.Save(path); err != nil {
+			return err
+		}
+	}
+
+	switch resp.Outcome {
+	case history.Correct:
+		return nil
+	case history.Wait:
+		return fmt.Errorf("submitted too recently, wait %v", resp.Wait)
+	default:
+		return fmt.Errorf("answer not accepted: %s", resp.Outcome)
+	}
+}
+
+// solve runs one part of a day on its default input.
+func solve(ctx context.Context, root string, number, part int, stderr io.Writer) (runner.Result, error) {
+	var stdout bytes.Buffer
+	cmd := dayCommand(ctx, root, number, "-part", strconv.Itoa(part), "-format", "json")
+	cmd.Stdout = &stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return runner.Result{}, fmt.Errorf("running day %d: %w", number, err)
+	}
+
+	var res runner.Result
+	if err := json.Unmarshal(stdout.Bytes(), &res); err != nil {
+		return res, fmt.Errorf("reading the answer of day %d: %w", number, err)
+	}
+	if res.Error != "" {
+		return res, errors.New(res.Error)
+	}
+	return res, nil
+}
+
+// hashFile returns the hash the runner reports for filename, or "" if it
+// cannot be read.
+func hashFile(filename string) string {
+	file, err := os.Open(filename)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"adventofcode2024/history"
+)
+
+// fakeAnswers stands in for the site: 75 is the answer to day 1 part 1.
+// Everything but the answer form is missing, so that a day run by the test
+// can never fetch a page and save it as its input.
+func fakeAnswers(t *testing.T) *[]string {
+	t.Helper()
+	var submitted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/2024/day/1/answer" {
+			http.NotFound(w, r)
+			return
+		}
+		answer := r.FormValue("answer")
+		submitted = append(submitted, answer)
+		n, _ := strconv.Atoi(answer)
+		var article string
+		switch {
+		case n == 75:
+			article = "That's the right answer!"
+		case n == 60:
+			article = "You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 30s left to wait."
+		case n > 75:
+			article = "That's not the right answer; your answer is too high."
+		default:
+			article = "That's not the right answer; your answer is too low."
+		}
+		fmt.Fprintf(w, "<html><body><main><article><p>%s</p></article></main></body></html>", article)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("AOC_BASE_URL", server.URL)
+	t.Setenv("AOC_SESSION", "secret")
+	t.Setenv("AOC_CACHE_DIR", t.TempDir())
+	t.Setenv("AOC_MIN_INTERVAL", "0s")
+	t.Setenv("AOC_HISTORY", filepath.Join(t.TempDir(), "history.json"))
+	return &submitted
+}
+
+func TestSubmit(t *testing.T) {
+	submitted := fakeAnswers(t)
+
+	steps := []struct {
+		answer  string
+		output  string
+		message string
+	}{
+		{answer: "100", output: "100 is too high", message: "not accepted"},
+		{answer: "100", message: "already rejected"},
+		{answer: "120", message: "outside the known bounds"},
+		{answer: "10", output: "10 is too low", message: "not accepted"},
+		{answer: "5", message: "outside the known bounds"},
+		{answer: "60", output: "60 is wait", message: "wait 30s"},
+		{answer: "75", output: "75 is correct"},
+		{answer: "76", message: "already solved with 75"},
+	}
+
+	for _, step := range steps {
+		stdout, _, err := runAoc(t, "submit", "1", "1", step.answer)
+		if step.message == "" && err != nil {
+			t.Errorf("submitting %s: unexpected error: %v", step.answer, err)
+		}
+		if step.message != "" && (err == nil || !strings.Contains(err.Error(), step.message)) {
+			t.Errorf("submitting %s: expected an error containing %q, got %v", step.answer, step.message, err)
+		}
+		if !strings.Contains(stdout, step.output) {
+			t.Errorf("submitting %s: expected output containing %q, got %q", step.answer, step.output, stdout)
+		}
+	}
+
+	if got := strings.Join(*submitted, " "); got != "100 10 60 75" {
+		t.Errorf("the site received %q", got)
+	}
+}
+
+func TestSubmit_Computed(t *testing.T) {
+	submitted := fakeAnswers(t)
+
+	if _, _, err := runAoc(t, "submit", "-root", "../..", "1", "2"); err != nil && !strings.Contains(err.Error(), "not accepted") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*submitted) != 1 {
+		t.Fatalf("expected one submission, got %v", *submitted)
+	}
+	if _, err := strconv.Atoi((*submitted)[0]); err != nil {
+		t.Errorf("submitted a non-numeric answer %q", (*submitted)[0])
+	}
+
+	h, err := history.LoadDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Attempts) != 1 || h.Attempts[0].InputHash != hashFile("../../day01/input") {
+		t.Errorf("expected the attempt to record the input hash, got %+v", h.Attempts)
+	}
+}
+
+func TestSubmit_Usage(t *testing.T) {
+	for _, args := range [][]string{{"submit"}, {"submit", "1"}, {"submit", "1", "3"}, {"submit", "1", "1", "x"}} {
+		if _, _, err := runAoc(t, args...); err == nil {
+			t.Errorf("aoc %v: expected an error", args)
+		}
+	}
+}
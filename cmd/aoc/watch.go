@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"adventofcode2024/runner"
+)
+
+func runWatch(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: aoc watch [flags] <day> [day flags]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Rebuilds, tests and runs a day every time a file in its directory changes,")
+		fmt.Fprintln(stderr, "and shows how the answers changed since the previous run. The day flags are passed on.")
+		fs.PrintDefaults()
+	}
+	root := fs.String("root", ".", "repository root")
+	interval := fs.Duration("interval", 500*time.Millisecond, "how often to look for changes")
+	once := fs.Bool("once", false, "run once and exit instead of watching")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return errUsage
+	}
+	number, err := registeredDay(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(*root, dayDir(number))
+
+	tmp, err := os.MkdirTemp("", "aoc-watch")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	binary := filepath.Join(tmp, dayDir(number))
+
+	var previous []runner.Result
+	before, err := snapshot(dir)
+	if err != nil {
+		return err
+	}
+	for {
+		results, err := cycle(ctx, dir, binary, fs.Args()[1:], stdout)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if *once {
+			io.WriteString(stdout, diffAnswers(nil, results))
+			return err
+		}
+		if err != nil {
+			fmt.Fprintln(stdout, err)
+		}
+		if results != nil {
+			io.WriteString(stdout, diffAnswers(previous, results))
+			previous = results
+		}
+
+		fmt.Fprintln(stdout, "waiting for changes...")
+		var changes []string
+		for len(changes) == 0 {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(*interval):
+			}
+			after, err := snapshot(dir)
+			if err != nil {
+				return err
+			}
+			changes = changed(before, after)
+			before = after
+		}
+		fmt.Fprintf(stdout, "\n[%s] changed: %s\n", time.Now().Format("15:04:05"), strings.Join(changes, ", "))
+	}
+}
+
+// cycle builds the day, runs its tests and then runs the binary on the
+// day's input. Build and test failures are returned as errors carrying the
+// tool's output. Failing tests do not stop the run, since that is when the
+// answers matter most, so their error comes back along with the results.
+func cycle(ctx context.Context, dir, binary string, args []string, stdout io.Writer) ([]runner.Result, error) {
+	start := time.Now()
+	if out, err := goCommand(ctx, dir, "build", "-o", binary, "."); err != nil {
+		return nil, fmt.Errorf("build failed:\n%s", out)
+	}
+	var testErr error
+	if out, err := goCommand(ctx, dir, "test", "."); err != nil {
+		testErr = fmt.Errorf("tests failed:\n%s", out)
+		fmt.Fprintf(stdout, "build ok (%v)\n", time.Since(start).Round(time.Millisecond))
+	} else {
+		fmt.Fprintf(stdout, "build and tests ok (%v)\n", time.Since(start).Round(time.Millisecond))
+	}
+
+	var out, errOut bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, append(args, "-format", "json")...)
+	cmd.Dir = dir
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+	runErr := cmd.Run()
+
+	var results []runner.Result
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var res runner.Result
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			return nil, errors.Join(testErr, fmt.Errorf("reading results: %w", err))
+		}
+		results = append(results, res)
+	}
+	if len(results) == 0 && runErr != nil {
+		return nil, errors.Join(testErr, fmt.Errorf("run failed: %v\n%s", runErr, errOut.String()))
+	}
+	return results, testErr
+}
+
+func goCommand(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	return cmd.CombinedOutput()
+}
+
+// diffAnswers describes results, one line per part, noting how each answer
+// compares with the one in previous.
+func diffAnswers(previous, results []runner.Result) string {
+	var sb strings.Builder
+	for _, res := range results {
+		fmt.Fprintf(&sb, "Part%d: ", res.Part)
+		if res.Error != "" {
+			sb.WriteString("error: " + res.Error)
+		} else {
+			fmt.Fprintf(&sb, "%d", res.Answer)
+		}
+
+		i := slices.IndexFunc(previous, func(p runner.Result) bool { return p.Part == res.Part })
+		switch {
+		case i < 0:
+		case previous[i].Error != "" && res.Error == "":
+			sb.WriteString(" (was an error)")
+		case previous[i].Error == "" && res.Error == "" && previous[i].Answer != res.Answer:
+			fmt.Fprintf(&sb, " (was %d)", previous[i].Answer)
+		case previous[i].Error == "" && res.Error == "":
+			sb.WriteString(" (unchanged)")
+		}
+		if res.Error == "" {
+			fmt.Fprintf(&sb, " in %v", res.Duration.Round(time.Microsecond))
+		}
+		if res.Warning != "" {
+			sb.WriteString(", warning: " + res.Warning)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// snapshot records the watched files under dir, so that polling can tell
+// when one is added, removed or modified.
+func snapshot(dir string) (map[string]fileState, error) {
+	files := map[string]fileState{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Files can disappear between listing and stat while an
+			// editor saves.
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if !watched(rel) {
+			return nil
+		}
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		files[rel] = fileState{info.ModTime(), info.Size()}
+		return nil
+	})
+	return files, err
+}
+
+// watched reports whether a file, given relative to the day directory, is
+// a source, an input or test data. Anything else, like the profiles a run
+// writes next to the input, must not set off another cycle.
+func watched(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	switch {
+	case strings.HasSuffix(rel, ".go"):
+		return true
+	case strings.HasPrefix(rel, "testdata/"):
+		return true
+	case strings.HasPrefix(rel, "input") && !strings.Contains(rel, "/"):
+		return true
+	}
+	return false
+}
+
+func changed(before, after map[string]fileState) []string {
+	var names []string
+	for name, state := range after {
+		if old, ok := before[name]; !ok || old != state {
+			names = append(names, name)
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
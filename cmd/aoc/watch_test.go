@@ -0,0 +1,157 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"adventofcode2024/runner"
+)
+
+func TestWatch_Once(t *testing.T) {
+	stdout, _, err := runAoc(t, "watch", "-root", "../..", "-once", "1", "-input", "testdata/example1.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"build and tests ok", "Part1: 11 in ", "Part2: 31 in "} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("output %q does not contain %q", stdout, want)
+		}
+	}
+}
+
+func TestWatch_FailingTests(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module watchtest\n",
+		"day01/main.go": `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println(` + "`" + `{"day":1,"part":1,"answer":7}` + "`" + `)
+}
+`,
+		"day01/main_test.go": `package main
+
+import "testing"
+
+func TestExample(t *testing.T) {
+	t.Error("still red")
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stdout, _, err := runAoc(t, "watch", "-root", root, "-once", "1")
+	if err == nil || !strings.Contains(err.Error(), "tests failed") {
+		t.Errorf("expected the test failure as error, got %v", err)
+	}
+	if !strings.Contains(stdout, "Part1: 7 in ") {
+		t.Errorf("output %q should still show the answer", stdout)
+	}
+}
+
+func TestDiffAnswers(t *testing.T) {
+	previous := []runner.Result{
+		{Part: 1, Answer: 10, Duration: time.Millisecond},
+		{Part: 2, Error: "not implemented"},
+	}
+	tests := []struct {
+		name     string
+		previous []runner.Result
+		results  []runner.Result
+		expected string
+	}{
+		{
+			name:     "first run",
+			results:  previous,
+			expected: "Part1: 10 in 1ms\nPart2: error: not implemented\n",
+		},
+		{
+			name:     "changes",
+			previous: previous,
+			results: []runner.Result{
+				{Part: 1, Answer: 11, Duration: 2 * time.Millisecond},
+				{Part: 2, Answer: 31, Duration: time.Microsecond, Warning: "31 was already rejected"},
+			},
+			expected: "Part1: 11 (was 10) in 2ms\nPart2: 31 (was an error) in 1µs, warning: 31 was already rejected\n",
+		},
+		{
+			name:     "unchanged",
+			previous: previous,
+			results:  previous[:1],
+			expected: "Part1: 10 (unchanged) in 1ms\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffAnswers(tt.previous, tt.results); got != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string, modTime time.Time) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Date(2024, 12, 1, 6, 0, 0, 0, time.UTC)
+	write("part1.go", "package main\n", start)
+	write("testdata/example1.txt", "1\n", start)
+	write("input", "1\n", start)
+	// Files a run writes, like profiles, are not watched.
+	write("cpu.day01.part1.prof", "", start)
+	write("out/trace.day01.part1", "", start)
+
+	before, err := snapshot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(before) != 3 {
+		t.Errorf("expected 3 files, got %v", before)
+	}
+
+	write("part1.go", "package main\n", start.Add(time.Second))
+	write("testdata/example2.txt", "2\n", start)
+	write("cpu.day01.part1.prof", "profile", start.Add(time.Second))
+	if err := os.Remove(filepath.Join(dir, "input")); err != nil {
+		t.Fatal(err)
+	}
+	after, err := snapshot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"input", "part1.go", filepath.Join("testdata", "example2.txt")}
+	if got := changed(before, after); !reflect.DeepEqual(got, expected) {
+		t.Errorf("changed() = %q, want %q", got, expected)
+	}
+	if got := changed(after, after); got != nil {
+		t.Errorf("changed() with no changes = %q", got)
+	}
+}
@@ -2,77 +2,63 @@ package main
 
 import (
 	"bytes"
-	"io"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
-)
-
-func TestMain(t *testing.T) {
-	tempInput := []byte(`3   4
-4   3
-2   5
-1   3
-3   9
-3   3`)
+	"testing/quick"
 
-	tmpfile, err := os.CreateTemp("", "input")
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer os.Remove(tmpfile.Name())
+	"adventofcode2024/aoctest"
+	"adventofcode2024/difftest"
+	"adventofcode2024/gen"
+	"adventofcode2024/input"
+	"adventofcode2024/runner"
+)
 
-	if _, err := tmpfile.Write(tempInput); err != nil {
-		t.Fatal(err)
-	}
-	if err := tmpfile.Close(); err != nil {
-		t.Fatal(err)
-	}
+func TestDay(t *testing.T) {
+	aoctest.Suite{
+		Day: day,
+		Examples: []aoctest.Example{
+			{
+				Name:  "empty input",
+				Input: "",
+				Part1: aoctest.Want(0),
+				Part2: aoctest.Want(0),
+			},
+			{
+				Name:  "single matching pair",
+				Input: "1   1\n",
+				Part1: aoctest.Want(0),
+				Part2: aoctest.Want(1),
+			},
+		},
+		Errors: []aoctest.ErrorCase{
+			{Name: "truncated gzip", Input: "\x1f\x8b"},
+			{Name: "unexpected argument", Input: "1 2", Args: []string{"extra"}},
+		},
+	}.Run(t)
+}
 
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, day)
+}
 
-	originalInput := "input"
-	if _, err := os.Stat(originalInput); err == nil {
-		tmpOriginal, err := os.CreateTemp("", "original_input")
-		if err != nil {
-			t.Fatal(err)
-		}
-		defer os.Remove(tmpOriginal.Name())
+func TestSolve_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
 
-		originalContent, err := os.ReadFile(originalInput)
-		if err != nil {
-			t.Fatal(err)
-		}
-		if err := os.WriteFile(tmpOriginal.Name(), originalContent, 0644); err != nil {
-			t.Fatal(err)
+	for i, solve := range []runner.Solver{Solve1, Solve2} {
+		if _, err := solve(ctx, strings.NewReader("1 2\n3 4\n")); !errors.Is(err, context.Canceled) {
+			t.Errorf("Part%d: expected context.Canceled, got %v", i+1, err)
 		}
-
-		defer func() {
-			os.Remove(originalInput)
-			os.Rename(tmpOriginal.Name(), originalInput)
-		}()
-	}
-
-	if err := os.Rename(tmpfile.Name(), originalInput); err != nil {
-		t.Fatal(err)
-	}
-
-	main()
-
-	w.Close()
-	os.Stdout = oldStdout
-
-	var buf bytes.Buffer
-	if _, err := io.Copy(&buf, r); err != nil {
-		t.Fatal(err)
-	}
-	output := buf.String()
-
-	expectedOutput := "Part1 result:  11\nPart2 result:  31\n"
-	if output != expectedOutput {
-		t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
 	}
 }
 
@@ -132,13 +118,13 @@ func TestSplitLine(t *testing.T) {
 func TestReadNumbersFromFile_Errors(t *testing.T) {
 	tests := []struct {
 		name        string
-		setup       func() string
+		filename    func() string
 		expectError bool
 		errorMsg    string
 	}{
 		{
 			name: "nonexistent file",
-			setup: func() string {
+			filename: func() string {
 				return "nonexistentfile.txt"
 			},
 			expectError: true,
@@ -146,47 +132,24 @@ func TestReadNumbersFromFile_Errors(t *testing.T) {
 		},
 		{
 			name: "empty file",
-			setup: func() string {
-				tmpfile, err := os.CreateTemp("", "empty")
-				if err != nil {
-					t.Fatal(err)
-				}
-				tmpfile.Close()
-				return tmpfile.Name()
+			filename: func() string {
+				return aoctest.TempFile(t, "")
 			},
 			expectError: false,
 			errorMsg:    "should handle empty file",
 		},
 		{
 			name: "invalid number format",
-			setup: func() string {
-				tmpfile, err := os.CreateTemp("", "invalid")
-				if err != nil {
-					t.Fatal(err)
-				}
-				content := []byte("abc def\n1 2")
-				if _, err := tmpfile.Write(content); err != nil {
-					t.Fatal(err)
-				}
-				tmpfile.Close()
-				return tmpfile.Name()
+			filename: func() string {
+				return aoctest.TempFile(t, "abc def\n1 2")
 			},
 			expectError: false,
 			errorMsg:    "should handle invalid number format",
 		},
 		{
 			name: "insufficient columns",
-			setup: func() string {
-				tmpfile, err := os.CreateTemp("", "insufficient")
-				if err != nil {
-					t.Fatal(err)
-				}
-				content := []byte("1\n1 2")
-				if _, err := tmpfile.Write(content); err != nil {
-					t.Fatal(err)
-				}
-				tmpfile.Close()
-				return tmpfile.Name()
+			filename: func() string {
+				return aoctest.TempFile(t, "1\n1 2")
 			},
 			expectError: false,
 			errorMsg:    "should handle insufficient columns",
@@ -195,12 +158,7 @@ func TestReadNumbersFromFile_Errors(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			filename := tt.setup()
-			if filename != "nonexistentfile.txt" {
-				defer os.Remove(filename)
-			}
-
-			nums1, nums2, err := ReadNumbersFromFile(filename)
+			nums1, nums2, err := ReadNumbersFromFile(tt.filename())
 
 			if tt.expectError {
 				if err == nil {
@@ -246,22 +204,17 @@ func TestReadNumbersFromFile_ValidContent(t *testing.T) {
 			expectedNums1: []int{1, 3},
 			expectedNums2: []int{2, 4},
 		},
+		{
+			name:          "extra fields",
+			content:       "1 2 x\n3 4 5\n6 y",
+			expectedNums1: []int{1, 3, 6},
+			expectedNums2: []int{2, 4, 0},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tmpfile, err := os.CreateTemp("", "test")
-			if err != nil {
-				t.Fatal(err)
-			}
-			defer os.Remove(tmpfile.Name())
-
-			if _, err := tmpfile.Write([]byte(tt.content)); err != nil {
-				t.Fatal(err)
-			}
-			tmpfile.Close()
-
-			nums1, nums2, err := ReadNumbersFromFile(tmpfile.Name())
+			nums1, nums2, err := ReadNumbersFromFile(aoctest.TempFile(t, tt.content))
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -276,79 +229,280 @@ func TestReadNumbersFromFile_ValidContent(t *testing.T) {
 	}
 }
 
-func TestPart1Error(t *testing.T) {
-	_, err := Part1("nonexistentfile")
-	if err == nil {
-		t.Error("Expected error for non-existent file")
+func TestReadNumbersFromFile_Gzip(t *testing.T) {
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write([]byte("3   4\n4   3\n2   5")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
 	}
-}
-
-func TestPart1(t *testing.T) {
-	tempInput := []byte(`3   4
-4   3
-2   5
-1   3
-3   9
-3   3`)
 
-	tmpfile, err := os.CreateTemp("", "example")
+	nums1, nums2, err := ReadNumbersFromFile(aoctest.TempFile(t, compressed.String()))
 	if err != nil {
-		t.Fatal(err)
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(nums1, []int{3, 4, 2}) {
+		t.Errorf("nums1 = %v, want %v", nums1, []int{3, 4, 2})
+	}
+	if !reflect.DeepEqual(nums2, []int{4, 3, 5}) {
+		t.Errorf("nums2 = %v, want %v", nums2, []int{4, 3, 5})
 	}
-	defer os.Remove(tmpfile.Name())
+}
 
-	if _, err := tmpfile.Write(tempInput); err != nil {
-		t.Fatal(err)
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name     string
+		nums1    []int
+		nums2    []int
+		expected int
+	}{
+		{name: "example", nums1: []int{3, 4, 2, 1, 3, 3}, nums2: []int{4, 3, 5, 3, 9, 3}, expected: 31},
+		{name: "no matches", nums1: []int{1, 2}, nums2: []int{3, 4}, expected: 0},
+		{name: "empty", nums1: []int{}, nums2: []int{}, expected: 0},
+		{name: "repeated on both sides", nums1: []int{2, 2}, nums2: []int{2, 2, 2}, expected: 12},
 	}
-	if err := tmpfile.Close(); err != nil {
-		t.Fatal(err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Similarity(context.Background(), tt.nums1, tt.nums2)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Similarity(%v, %v) = %d, want %d", tt.nums1, tt.nums2, result, tt.expected)
+			}
+			result = SimilarityBruteForce(tt.nums1, tt.nums2)
+			if result != tt.expected {
+				t.Errorf("SimilarityBruteForce(%v, %v) = %d, want %d", tt.nums1, tt.nums2, result, tt.expected)
+			}
+		})
 	}
+}
 
-	result, err := Part1(tmpfile.Name())
-	if err != nil {
-		t.Fatalf("Process failed: %v", err)
+func TestSimilarity_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Similarity(ctx, []int{1, 2}, []int{2, 3}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
 	}
+}
 
-	expected := 11
-	if result != expected {
-		t.Errorf("Expected output %d, got %d", expected, result)
+func TestSimilarity_Differential(t *testing.T) {
+	fast := func(nums1, nums2 []int) int {
+		result, err := Similarity(context.Background(), nums1, nums2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
 	}
+	columns := func(similarity func([]int, []int) int) func([][2]int) int {
+		return func(pairs [][2]int) int {
+			nums1 := make([]int, len(pairs))
+			nums2 := make([]int, len(pairs))
+			for i, pair := range pairs {
+				nums1[i], nums2[i] = pair[0], pair[1]
+			}
+			return similarity(nums1, nums2)
+		}
+	}
+
+	difftest.Check(t, difftest.Pair[[2]int, int]{
+		Name:      "Similarity",
+		Reference: columns(SimilarityBruteForce),
+		Fast:      columns(fast),
+		Generate: func(r *rand.Rand) [][2]int {
+			pairs, err := gen.Day01(r, gen.Day01Options{
+				Lines:         r.IntN(50),
+				Min:           1,
+				Max:           1 + r.IntN(30),
+				DuplicateRate: r.Float64(),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return pairs
+		},
+	})
 }
 
-func TestPart2Error(t *testing.T) {
-	_, err := Part2("nonexistentfile")
-	if err == nil {
-		t.Error("Expected error for non-existent file")
+func FuzzSplitLine(f *testing.F) {
+	f.Add("3   4")
+	f.Add("3\t4")
+	f.Add("1")
+	f.Add("abc def")
+	f.Add("-5 +7 9")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, line string) {
+		nums := SplitLine(line)
+		if len(nums) != 2 {
+			t.Fatalf("SplitLine(%q) returned %d numbers", line, len(nums))
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			if nums[0] != 0 || nums[1] != 0 {
+				t.Errorf("SplitLine(%q) = %v, want zeros", line, nums)
+			}
+			return
+		}
+		for i := 0; i < 2; i++ {
+			if num, err := strconv.Atoi(fields[i]); err == nil && num != nums[i] {
+				t.Errorf("SplitLine(%q)[%d] = %d, want %d", line, i, nums[i], num)
+			}
+		}
+	})
+}
+
+func FuzzReadNumbersFromFile(f *testing.F) {
+	f.Add([]byte("3   4\n4   3\n2   5"))
+	f.Add([]byte("1\n\n1 2\r\n"))
+	f.Add([]byte{0x1f, 0x8b})
+	f.Add([]byte(""))
+
+	f.Fuzz(func(t *testing.T, content []byte) {
+		nums1, nums2, err := ReadNumbersFromFile(aoctest.TempFile(t, string(content)))
+		if err != nil {
+			return
+		}
+		if len(nums1) != len(nums2) {
+			t.Errorf("lists have different lengths: %d and %d", len(nums1), len(nums2))
+		}
+	})
+}
+
+func writeColumns(t *testing.T, pairs [][2]int16, swap bool) string {
+	t.Helper()
+	var sb strings.Builder
+	for _, pair := range pairs {
+		left, right := pair[0], pair[1]
+		if swap {
+			left, right = right, left
+		}
+		fmt.Fprintf(&sb, "%d   %d\n", left, right)
 	}
+	return aoctest.TempFile(t, sb.String())
 }
 
-func TestPart2(t *testing.T) {
-	tempInput := []byte(`3   4
-4   3
-2   5
-1   3
-3   9
-3   3`)
-	tmpfile, err := os.CreateTemp("", "example")
-	if err != nil {
-		t.Fatal(err)
+func TestPart1_Symmetric(t *testing.T) {
+	property := func(pairs [][2]int16) bool {
+		result, err := Part1(writeColumns(t, pairs, false))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		swapped, err := Part1(writeColumns(t, pairs, true))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result == swapped
 	}
-	defer os.Remove(tmpfile.Name())
 
-	if _, err := tmpfile.Write(tempInput); err != nil {
-		t.Fatal(err)
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
 	}
-	if err := tmpfile.Close(); err != nil {
-		t.Fatal(err)
+}
+
+func TestPart1_OrderIndependent(t *testing.T) {
+	property := func(pairs [][2]int16, seed uint64) bool {
+		result, err := Part1(writeColumns(t, pairs, false))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		shuffled := append([][2]int16{}, pairs...)
+		rand.New(rand.NewPCG(seed, seed)).Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i][0], shuffled[j][0] = shuffled[j][0], shuffled[i][0]
+		})
+		other, err := Part1(writeColumns(t, shuffled, false))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result == other
 	}
 
-	result, err := Part2(tmpfile.Name())
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+var millionLines = sync.OnceValue(func() []byte {
+	opts := gen.DefaultDay01Options
+	opts.Lines = 1_000_000
+	pairs, err := gen.Day01(gen.NewRand(1), opts)
 	if err != nil {
-		t.Fatalf("Process failed: %v", err)
+		panic(err)
+	}
+	var buf bytes.Buffer
+	if err := gen.WriteDay01(&buf, pairs); err != nil {
+		panic(err)
 	}
+	return buf.Bytes()
+})
+
+func BenchmarkReadNumbers(b *testing.B) {
+	content := millionLines()
+	b.SetBytes(int64(len(content)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := ReadNumbers(context.Background(), bytes.NewReader(content)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReadNumbers_SplitLine is the string based loop ReadNumbers used
+// to run, kept as the baseline.
+func BenchmarkReadNumbers_SplitLine(b *testing.B) {
+	content := millionLines()
+	b.SetBytes(int64(len(content)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var nums1, nums2 []int
+		for line, err := range input.Lines(bytes.NewReader(content)) {
+			if err != nil {
+				b.Fatal(err)
+			}
+			nums := SplitLine(line.Value)
+			nums1 = append(nums1, nums[0])
+			nums2 = append(nums2, nums[1])
+		}
+	}
+}
 
-	expected := 31
-	if result != expected {
-		t.Errorf("Expected output %d, got %d", expected, result)
+func BenchmarkReadNumbersFromFile(b *testing.B) {
+	name := filepath.Join(b.TempDir(), "input")
+	content := millionLines()
+	if err := os.WriteFile(name, content, 0644); err != nil {
+		b.Fatal(err)
 	}
+
+	b.Run("mapped", func(b *testing.B) {
+		b.SetBytes(int64(len(content)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, _, err := ReadNumbersFromFile(name); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("read", func(b *testing.B) {
+		b.SetBytes(int64(len(content)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			file, err := os.Open(name)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_, _, err = ReadNumbers(context.Background(), file)
+			file.Close()
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
 }
@@ -1,48 +1,84 @@
 package main
 
 import (
-	"bufio"
-	"fmt"
+	"context"
+	"io"
 	"log"
 	"os"
-	"strconv"
+	"os/signal"
 	"strings"
+
+	"adventofcode2024/client"
+	"adventofcode2024/history"
+	"adventofcode2024/input"
+	"adventofcode2024/parse"
+	"adventofcode2024/progress"
+	"adventofcode2024/runner"
 )
 
+var day = runner.Day{Number: 1, Part1: Solve1, Part2: Solve2}
+
 func main() {
-	result, err := Part1("input")
-	if err != nil {
-		log.Fatal(err)
-	}
-	fmt.Println("Part1 result: ", result)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	result, err = Part2("input")
+	// Without a readable history the answers just go unchecked.
+	answers, err := history.LoadDefault()
 	if err != nil {
+		log.Printf("not checking answers: %v", err)
+	}
+	env := runner.Env{
+		Stdin:   os.Stdin,
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+		FS:      runner.OSFS{},
+		Fetch:   client.SaveInput("input"),
+		History: answers,
+	}
+	if err := runner.Run(ctx, day, os.Args[1:], env); err != nil {
 		log.Fatal(err)
 	}
-	fmt.Println("Part2 result: ", result)
 }
 
 func ReadNumbersFromFile(filename string) ([]int, []int, error) {
-	file, err := os.Open(filename)
+	file, err := input.Open(filename)
 	if err != nil {
 		return nil, nil, err
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	return ReadNumbers(context.Background(), file)
+}
+
+func ReadNumbers(ctx context.Context, r io.Reader) ([]int, []int, error) {
 	nums1 := make([]int, 0)
 	nums2 := make([]int, 0)
+	rep := progress.FromContext(ctx)
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		nums := SplitLine(line)
-		nums1 = append(nums1, nums[0])
-		nums2 = append(nums2, nums[1])
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, nil, err
+	var fields []int
+	for line, err := range input.ByteLines(r) {
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := runner.Checkpoint(ctx, len(nums1)); err != nil {
+			return nil, nil, err
+		}
+		if rep != nil {
+			rep.AddRows(1)
+		}
+		var left, right int
+		fields, err = parse.AppendInts(fields[:0], line.Value)
+		switch {
+		case err != nil:
+			// SplitLine decides what odd lines are worth, so they keep
+			// reading the same whichever way they come in.
+			nums := SplitLine(string(line.Value))
+			left, right = nums[0], nums[1]
+		case len(fields) >= 2:
+			left, right = fields[0], fields[1]
+		}
+		nums1 = append(nums1, left)
+		nums2 = append(nums2, right)
 	}
 
 	return nums1, nums2, nil
@@ -52,8 +88,8 @@ func SplitLine(line string) []int {
 	nums := make([]int, 2)
 	fields := strings.Fields(line)
 	if len(fields) >= 2 {
-		nums[0], _ = strconv.Atoi(fields[0])
-		nums[1], _ = strconv.Atoi(fields[1])
+		nums[0], _ = parse.Int(fields[0])
+		nums[1], _ = parse.Int(fields[1])
 	}
 	return nums
 }
@@ -1,11 +1,22 @@
 package main
 
 import (
+	"context"
+	"io"
 	"sort"
+
+	"adventofcode2024/input"
+	"adventofcode2024/runner"
 )
 
 func Part1(filename string) (int, error) {
-	nums1, nums2, err := ReadNumbersFromFile(filename)
+	return input.FromFile(filename, func(r io.Reader) (int, error) {
+		return Solve1(context.Background(), r)
+	})
+}
+
+func Solve1(ctx context.Context, r io.Reader) (int, error) {
+	nums1, nums2, err := ReadNumbers(ctx, r)
 	if err != nil {
 		return 0, err
 	}
@@ -15,6 +26,9 @@ func Part1(filename string) (int, error) {
 
 	sum := 0
 	for i := 0; i < len(nums1); i++ {
+		if err := runner.Checkpoint(ctx, i); err != nil {
+			return 0, err
+		}
 		if nums1[i] >= nums2[i] {
 			sum += nums1[i] - nums2[i]
 		} else {
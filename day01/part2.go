@@ -1,23 +1,53 @@
 package main
 
 import (
-	"sort"
+	"context"
+	"io"
+
+	"adventofcode2024/input"
+	"adventofcode2024/runner"
 )
 
 func Part2(filename string) (int, error) {
-	nums1, nums2, err := ReadNumbersFromFile(filename)
+	return input.FromFile(filename, func(r io.Reader) (int, error) {
+		return Solve2(context.Background(), r)
+	})
+}
+
+func Solve2(ctx context.Context, r io.Reader) (int, error) {
+	nums1, nums2, err := ReadNumbers(ctx, r)
 	if err != nil {
 		return 0, err
 	}
 
-	sort.Ints(nums1)
-	sort.Ints(nums2)
+	return Similarity(ctx, nums1, nums2)
+}
+
+func Similarity(ctx context.Context, nums1, nums2 []int) (int, error) {
+	counts := make(map[int]int, len(nums2))
+	for i, num := range nums2 {
+		if err := runner.Checkpoint(ctx, i); err != nil {
+			return 0, err
+		}
+		counts[num]++
+	}
+
+	sum := 0
+	for i, num := range nums1 {
+		if err := runner.Checkpoint(ctx, i); err != nil {
+			return 0, err
+		}
+		sum += num * counts[num]
+	}
+	return sum, nil
+}
 
+func SimilarityBruteForce(nums1, nums2 []int) int {
 	sum := 0
 	for i := 0; i < len(nums1); i++ {
 		sum += nums1[i] * Count(nums1[i], nums2)
 	}
-	return sum, nil
+	return sum
 }
 
 func Count(num int, nums []int) int {
@@ -2,77 +2,61 @@ package main
 
 import (
 	"bytes"
-	"io"
+	"context"
+	"errors"
+	"math/rand/v2"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
-)
-
-func TestMain(t *testing.T) {
-	tempInput := []byte(`7 6 4 2 1
-1 2 7 8 9
-9 7 6 2 1
-1 3 2 4 5
-8 6 4 4 1
-1 3 6 7 9`)
+	"testing/quick"
 
-	tmpfile, err := os.CreateTemp("", "input")
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer os.Remove(tmpfile.Name())
+	"adventofcode2024/aoctest"
+	"adventofcode2024/difftest"
+	"adventofcode2024/gen"
+	"adventofcode2024/input"
+	"adventofcode2024/runner"
+)
 
-	if _, err := tmpfile.Write(tempInput); err != nil {
-		t.Fatal(err)
-	}
-	if err := tmpfile.Close(); err != nil {
-		t.Fatal(err)
-	}
+func TestDay(t *testing.T) {
+	aoctest.Suite{
+		Day: day,
+		Examples: []aoctest.Example{
+			{
+				Name:  "empty input",
+				Input: "",
+				Part1: aoctest.Want(0),
+				Part2: aoctest.Want(0),
+			},
+			{
+				Name:  "invalid content",
+				Input: "invalid content\n",
+				Part1: aoctest.Want(0),
+				Part2: aoctest.Want(0),
+			},
+		},
+		Errors: []aoctest.ErrorCase{
+			{Name: "truncated gzip", Input: "\x1f\x8b"},
+			{Name: "unexpected argument", Input: "1 2", Args: []string{"extra"}},
+		},
+	}.Run(t)
+}
 
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, day)
+}
 
-	originalInput := "input"
-	if _, err := os.Stat(originalInput); err == nil {
-		tmpOriginal, err := os.CreateTemp("", "original_input")
-		if err != nil {
-			t.Fatal(err)
-		}
-		defer os.Remove(tmpOriginal.Name())
+func TestSolve_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
 
-		originalContent, err := os.ReadFile(originalInput)
-		if err != nil {
-			t.Fatal(err)
-		}
-		if err := os.WriteFile(tmpOriginal.Name(), originalContent, 0644); err != nil {
-			t.Fatal(err)
+	for i, solve := range []runner.Solver{Solve1, Solve2} {
+		if _, err := solve(ctx, strings.NewReader("1 2\n3 4\n")); !errors.Is(err, context.Canceled) {
+			t.Errorf("Part%d: expected context.Canceled, got %v", i+1, err)
 		}
-
-		defer func() {
-			os.Remove(originalInput)
-			os.Rename(tmpOriginal.Name(), originalInput)
-		}()
-	}
-
-	if err := os.Rename(tmpfile.Name(), originalInput); err != nil {
-		t.Fatal(err)
-	}
-
-	main()
-
-	w.Close()
-	os.Stdout = oldStdout
-
-	var buf bytes.Buffer
-	if _, err := io.Copy(&buf, r); err != nil {
-		t.Fatal(err)
-	}
-	output := buf.String()
-
-	expectedOutput := "Part1 result:  2\nPart2 result:  4\n"
-	if output != expectedOutput {
-		t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
 	}
 }
 
@@ -161,13 +145,13 @@ func TestSplitLine(t *testing.T) {
 func TestReadRowsFromFile_Errors(t *testing.T) {
 	tests := []struct {
 		name        string
-		setup       func() string
+		filename    func() string
 		expectError bool
 		errorMsg    string
 	}{
 		{
 			name: "nonexistent file",
-			setup: func() string {
+			filename: func() string {
 				return "nonexistentfile.txt"
 			},
 			expectError: true,
@@ -175,47 +159,24 @@ func TestReadRowsFromFile_Errors(t *testing.T) {
 		},
 		{
 			name: "empty file",
-			setup: func() string {
-				tmpfile, err := os.CreateTemp("", "empty")
-				if err != nil {
-					t.Fatal(err)
-				}
-				tmpfile.Close()
-				return tmpfile.Name()
+			filename: func() string {
+				return aoctest.TempFile(t, "")
 			},
 			expectError: false,
 			errorMsg:    "should handle empty file",
 		},
 		{
 			name: "invalid number format",
-			setup: func() string {
-				tmpfile, err := os.CreateTemp("", "invalid")
-				if err != nil {
-					t.Fatal(err)
-				}
-				content := []byte("abc def\n1 2")
-				if _, err := tmpfile.Write(content); err != nil {
-					t.Fatal(err)
-				}
-				tmpfile.Close()
-				return tmpfile.Name()
+			filename: func() string {
+				return aoctest.TempFile(t, "abc def\n1 2")
 			},
 			expectError: false,
 			errorMsg:    "should handle invalid number format",
 		},
 		{
 			name: "insufficient columns",
-			setup: func() string {
-				tmpfile, err := os.CreateTemp("", "insufficient")
-				if err != nil {
-					t.Fatal(err)
-				}
-				content := []byte("1\n1 2")
-				if _, err := tmpfile.Write(content); err != nil {
-					t.Fatal(err)
-				}
-				tmpfile.Close()
-				return tmpfile.Name()
+			filename: func() string {
+				return aoctest.TempFile(t, "1\n1 2")
 			},
 			expectError: false,
 			errorMsg:    "should handle insufficient columns",
@@ -224,12 +185,7 @@ func TestReadRowsFromFile_Errors(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			filename := tt.setup()
-			if filename != "nonexistentfile.txt" {
-				defer os.Remove(filename)
-			}
-
-			rows, err := ReadRowsFromFile(filename)
+			rows, err := ReadRowsFromFile(tt.filename())
 
 			if tt.expectError {
 				if err == nil {
@@ -240,10 +196,7 @@ func TestReadRowsFromFile_Errors(t *testing.T) {
 					t.Errorf("unexpected error: %v", err)
 				}
 				if rows == nil {
-					t.Error("nums1 should not be nil")
-				}
-				if rows == nil {
-					t.Error("nums2 should not be nil")
+					t.Error("rows should not be nil")
 				}
 			}
 		})
@@ -270,18 +223,7 @@ func TestReadRowsFromFile_ValidContent(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tmpfile, err := os.CreateTemp("", "test")
-			if err != nil {
-				t.Fatal(err)
-			}
-			defer os.Remove(tmpfile.Name())
-
-			if _, err := tmpfile.Write([]byte(tt.content)); err != nil {
-				t.Fatal(err)
-			}
-			tmpfile.Close()
-
-			rows, err := ReadRowsFromFile(tmpfile.Name())
+			rows, err := ReadRowsFromFile(aoctest.TempFile(t, tt.content))
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -293,6 +235,87 @@ func TestReadRowsFromFile_ValidContent(t *testing.T) {
 	}
 }
 
+func TestReadRowsFromFile_LongLine(t *testing.T) {
+	levels := 300000
+	var sb strings.Builder
+	for i := 0; i < levels; i++ {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(strconv.Itoa(i % 7))
+	}
+	sb.WriteString("\n1 2 3")
+
+	rows, err := ReadRowsFromFile(aoctest.TempFile(t, sb.String()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, expected 2", len(rows))
+	}
+	if len(rows[0]) != levels {
+		t.Errorf("first row has %d levels, expected %d", len(rows[0]), levels)
+	}
+}
+
+func TestRows(t *testing.T) {
+	rows := make([][]int, 0)
+	for row, err := range Rows(aoctest.TempFile(t, "1 2 3\ninvalid\n4 5\n6 7 8 9")) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rows = append(rows, row)
+		if len(rows) == 2 {
+			break
+		}
+	}
+
+	expected := [][]int{{1, 2, 3}, {4, 5}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Rows() = %v, want %v", rows, expected)
+	}
+}
+
+func TestRows_Error(t *testing.T) {
+	count := 0
+	for _, err := range Rows("nonexistentfile.txt") {
+		count++
+		if err == nil {
+			t.Error("should error on nonexistent file")
+		}
+	}
+	if count != 1 {
+		t.Errorf("Rows() yielded %d times, expected 1", count)
+	}
+}
+
+func TestCountRows(t *testing.T) {
+	result, err := CountRows(aoctest.TempFile(t, "1 2\n3 4 5\n6 7 8 9"), func(row []int) bool {
+		return len(row) > 2
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != 2 {
+		t.Errorf("CountRows() = %d, want 2", result)
+	}
+
+	// Rows with negative or unreadable levels are skipped, not counted.
+	result, err = CountRows(aoctest.TempFile(t, "1 2 3\n-1 2 3\n1 x 3\n4 5 6 7"), func(row []int) bool {
+		return len(row) > 2
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != 2 {
+		t.Errorf("CountRows() with invalid rows = %d, want 2", result)
+	}
+
+	if _, err := CountRows("nonexistentfile.txt", IsSafe); err == nil {
+		t.Error("should error on nonexistent file")
+	}
+}
+
 func TestIsInOrder(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -474,45 +497,6 @@ func TestIsSafe(t *testing.T) {
 	}
 }
 
-func TestPart1Error(t *testing.T) {
-	_, err := Part1("nonexistentfile")
-	if err == nil {
-		t.Error("Expected error for non-existent file")
-	}
-}
-
-func TestPart1(t *testing.T) {
-	tempInput := []byte(`7 6 4 2 1
-1 2 7 8 9
-9 7 6 2 1
-1 3 2 4 5
-8 6 4 4 1
-1 3 6 7 9`)
-
-	tmpfile, err := os.CreateTemp("", "example")
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer os.Remove(tmpfile.Name())
-
-	if _, err := tmpfile.Write(tempInput); err != nil {
-		t.Fatal(err)
-	}
-	if err := tmpfile.Close(); err != nil {
-		t.Fatal(err)
-	}
-
-	result, err := Part1(tmpfile.Name())
-	if err != nil {
-		t.Fatalf("Process failed: %v", err)
-	}
-
-	expected := 2
-	if result != expected {
-		t.Errorf("Expected output %d, got %d", expected, result)
-	}
-}
-
 func TestCanBeMadeSafe(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -578,81 +562,278 @@ func TestCanBeMadeSafe(t *testing.T) {
 				t.Errorf("CanBeMadeSafe(%v) = %v, want %v",
 					tt.input, result, tt.expected)
 			}
+			result = CanBeMadeSafeBruteForce(tt.input)
+			if result != tt.expected {
+				t.Errorf("CanBeMadeSafeBruteForce(%v) = %v, want %v",
+					tt.input, result, tt.expected)
+			}
 		})
 	}
 }
 
-func TestPart2Error(t *testing.T) {
-	_, err := Part2("nonexistentfile")
-	if err == nil {
-		t.Error("Expected error for non-existent file")
+func TestIsSafeStep(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to int
+		dir      int
+		expected bool
+	}{
+		{name: "increase of one", from: 1, to: 2, dir: 1, expected: true},
+		{name: "increase of three", from: 1, to: 4, dir: 1, expected: true},
+		{name: "increase of four", from: 1, to: 5, dir: 1, expected: false},
+		{name: "equal", from: 2, to: 2, dir: 1, expected: false},
+		{name: "wrong direction", from: 2, to: 1, dir: 1, expected: false},
+		{name: "decrease", from: 4, to: 2, dir: -1, expected: true},
+		{name: "decrease of four", from: 5, to: 1, dir: -1, expected: false},
 	}
 
-	tmpfile, err := os.CreateTemp("", "empty")
-	if err != nil {
-		t.Fatal(err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := IsSafeStep(tt.from, tt.to, tt.dir)
+			if result != tt.expected {
+				t.Errorf("IsSafeStep(%d, %d, %d) = %v, want %v",
+					tt.from, tt.to, tt.dir, result, tt.expected)
+			}
+		})
 	}
-	defer os.Remove(tmpfile.Name())
+}
 
-	result, err := Part2(tmpfile.Name())
-	if err != nil {
-		t.Fatalf("Unexpected error for empty file: %v", err)
-	}
-	if result != 0 {
-		t.Errorf("Expected 0 for empty file, got %d", result)
+func TestIsSafeWithout(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []int
+		skip     int
+		dir      int
+		expected bool
+	}{
+		{name: "skip the bad level", input: []int{1, 3, 2, 4, 5}, skip: 1, dir: 1, expected: true},
+		{name: "skip a good level", input: []int{1, 3, 2, 4, 5}, skip: 0, dir: 1, expected: false},
+		{name: "skip the first level", input: []int{9, 1, 2, 3}, skip: 0, dir: 1, expected: true},
+		{name: "skip the last level", input: []int{5, 4, 3, 9}, skip: 3, dir: -1, expected: true},
+		{name: "gap too big after skipping", input: []int{1, 2, 3, 7}, skip: 2, dir: 1, expected: false},
 	}
 
-	invalidInput := []byte("invalid content\n")
-	tmpfileInvalid, err := os.CreateTemp("", "invalid")
-	if err != nil {
-		t.Fatal(err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := IsSafeWithout(tt.input, tt.skip, tt.dir)
+			if result != tt.expected {
+				t.Errorf("IsSafeWithout(%v, %d, %d) = %v, want %v",
+					tt.input, tt.skip, tt.dir, result, tt.expected)
+			}
+		})
 	}
-	defer os.Remove(tmpfileInvalid.Name())
+}
 
-	if _, err := tmpfileInvalid.Write(invalidInput); err != nil {
-		t.Fatal(err)
+func TestCanBeMadeSafe_Differential(t *testing.T) {
+	opts := gen.Day02Options{Reports: 1, MinLevels: 4, MaxLevels: 10, Min: 1, Max: 20}
+	difftest.Check(t, difftest.Pair[int, bool]{
+		Name:      "CanBeMadeSafe",
+		Reference: CanBeMadeSafeBruteForce,
+		Fast:      CanBeMadeSafe,
+		Generate: func(r *rand.Rand) []int {
+			if r.IntN(4) == 0 {
+				row := make([]int, r.IntN(8))
+				for i := range row {
+					row[i] = r.IntN(10)
+				}
+				return row
+			}
+			return gen.Report(r, opts, gen.ReportKind(r.IntN(3)))
+		},
+	})
+}
+
+func FuzzSplitLine(f *testing.F) {
+	f.Add("7 6 4 2 1")
+	f.Add("1 a 3")
+	f.Add("-1 2 -3")
+	f.Add("+4 5")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, line string) {
+		nums := SplitLine(line)
+		if nums == nil {
+			return
+		}
+		if len(nums) != len(strings.Fields(line)) {
+			t.Errorf("SplitLine(%q) = %v, lost fields", line, nums)
+		}
+		for _, num := range nums {
+			if num < 0 {
+				t.Errorf("SplitLine(%q) = %v, contains a negative number", line, nums)
+			}
+		}
+	})
+}
+
+func FuzzReadRowsFromFile(f *testing.F) {
+	f.Add([]byte("7 6 4 2 1\n1 2 7 8 9"))
+	f.Add([]byte("abc def\n\n1 2\r\n"))
+	f.Add([]byte{0x1f, 0x8b})
+	f.Add([]byte(""))
+
+	f.Fuzz(func(t *testing.T, content []byte) {
+		filename := aoctest.TempFile(t, string(content))
+		rows, err := ReadRowsFromFile(filename)
+		if err != nil {
+			return
+		}
+		for _, row := range rows {
+			if len(row) == 0 {
+				t.Errorf("ReadRowsFromFile() returned an empty row")
+			}
+		}
+
+		safe, err := Part1(filename)
+		if err != nil {
+			t.Fatalf("Part1 failed after a successful read: %v", err)
+		}
+		dampened, err := Part2(filename)
+		if err != nil {
+			t.Fatalf("Part2 failed after a successful read: %v", err)
+		}
+		if safe > dampened || dampened > len(rows) {
+			t.Errorf("expected Part1 <= Part2 <= rows, got %d, %d, %d", safe, dampened, len(rows))
+		}
+	})
+}
+
+// reports mixes quick's random rows, which are almost never safe, with
+// generated ones of every kind so that both sides of each property are hit.
+func reports(t *testing.T) [][]int {
+	t.Helper()
+	rows := make([][]int, 0)
+	property := func(row []int8) bool {
+		levels := make([]int, len(row))
+		for i, level := range row {
+			levels[i] = int(level)
+		}
+		rows = append(rows, levels)
+		return true
 	}
-	if err := tmpfileInvalid.Close(); err != nil {
+	if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
 		t.Fatal(err)
 	}
 
-	result, err = Part2(tmpfileInvalid.Name())
+	generated, err := gen.Day02(gen.NewRand(1), gen.Day02Options{
+		Reports:        1500,
+		MinLevels:      4,
+		MaxLevels:      9,
+		Min:            1,
+		Max:            30,
+		SafeRate:       0.34,
+		DampenableRate: 0.33,
+	})
 	if err != nil {
-		t.Fatalf("Unexpected error for invalid content: %v", err)
+		t.Fatal(err)
 	}
-	if result != 0 {
-		t.Errorf("Expected 0 for invalid content, got %d", result)
+	return append(rows, generated...)
+}
+
+func reversed(row []int) []int {
+	result := make([]int, len(row))
+	for i, level := range row {
+		result[len(row)-1-i] = level
 	}
+	return result
 }
 
-func TestPart2(t *testing.T) {
-	tempInput := []byte(`7 6 4 2 1
-1 2 7 8 9
-9 7 6 2 1
-1 3 2 4 5
-8 6 4 4 1
-1 3 6 7 9`)
+func TestIsSafe_ReverseInvariant(t *testing.T) {
+	for _, row := range reports(t) {
+		if IsSafe(row) != IsSafe(reversed(row)) {
+			t.Fatalf("IsSafe(%v) != IsSafe(%v)", row, reversed(row))
+		}
+	}
+}
 
-	tmpfile, err := os.CreateTemp("", "example")
-	if err != nil {
-		t.Fatal(err)
+func TestCanBeMadeSafe_ReverseInvariant(t *testing.T) {
+	for _, row := range reports(t) {
+		if CanBeMadeSafe(row) != CanBeMadeSafe(reversed(row)) {
+			t.Fatalf("CanBeMadeSafe(%v) != CanBeMadeSafe(%v)", row, reversed(row))
+		}
 	}
-	defer os.Remove(tmpfile.Name())
+}
 
-	if _, err := tmpfile.Write(tempInput); err != nil {
-		t.Fatal(err)
+func TestIsSafe_ImpliesCanBeMadeSafe(t *testing.T) {
+	for _, row := range reports(t) {
+		if IsSafe(row) && !CanBeMadeSafe(row) {
+			t.Fatalf("IsSafe(%v) but not CanBeMadeSafe", row)
+		}
 	}
-	if err := tmpfile.Close(); err != nil {
-		t.Fatal(err)
+}
+
+func TestIsSafe_ShiftInvariant(t *testing.T) {
+	property := func(row []int8, offset int16) bool {
+		levels := make([]int, len(row))
+		shifted := make([]int, len(row))
+		for i, level := range row {
+			levels[i] = int(level)
+			shifted[i] = int(level) + int(offset)
+		}
+		return IsSafe(levels) == IsSafe(shifted)
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
 	}
+}
 
-	result, err := Part2(tmpfile.Name())
+var millionReports = sync.OnceValue(func() []byte {
+	opts := gen.DefaultDay02Options
+	opts.Reports = 1_000_000
+	reports, err := gen.Day02(gen.NewRand(1), opts)
 	if err != nil {
-		t.Fatalf("Part2 failed: %v", err)
+		panic(err)
 	}
+	var buf bytes.Buffer
+	if err := gen.WriteDay02(&buf, reports); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+})
+
+func BenchmarkCountMatching(b *testing.B) {
+	content := millionReports()
+	b.SetBytes(int64(len(content)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := CountMatching(context.Background(), bytes.NewReader(content), IsSafe); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
 
-	expected := 4
-	if result != expected {
-		t.Errorf("Expected output %d, got %d", expected, result)
+// BenchmarkCountMatching_SplitLine is the string based loop CountMatching
+// used to run, kept as the baseline.
+func BenchmarkCountMatching_SplitLine(b *testing.B) {
+	content := millionReports()
+	b.SetBytes(int64(len(content)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		count := 0
+		for line, err := range input.Lines(bytes.NewReader(content)) {
+			if err != nil {
+				b.Fatal(err)
+			}
+			if row := SplitLine(line.Value); len(row) > 0 && IsSafe(row) {
+				count++
+			}
+		}
+	}
+}
+
+func BenchmarkReadRowsFromFile(b *testing.B) {
+	name := filepath.Join(b.TempDir(), "input")
+	if err := os.WriteFile(name, millionReports(), 0644); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ReadRowsFromFile(name); err != nil {
+			b.Fatal(err)
+		}
 	}
 }
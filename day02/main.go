@@ -1,67 +1,156 @@
 package main
 
 import (
-	"bufio"
-	"fmt"
+	"context"
+	"io"
+	"iter"
 	"log"
 	"os"
-	"strconv"
-	"strings"
+	"os/signal"
+	"slices"
+
+	"adventofcode2024/client"
+	"adventofcode2024/history"
+	"adventofcode2024/input"
+	"adventofcode2024/parse"
+	"adventofcode2024/progress"
+	"adventofcode2024/runner"
 )
 
+var day = runner.Day{Number: 2, Part1: Solve1, Part2: Solve2}
+
 func main() {
-	result, err := Part1("input")
-	if err != nil {
-		log.Fatal(err)
-	}
-	fmt.Println("Part1 result: ", result)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	result, err = Part2("input")
+	// Without a readable history the answers just go unchecked.
+	answers, err := history.LoadDefault()
 	if err != nil {
+		log.Printf("not checking answers: %v", err)
+	}
+	env := runner.Env{
+		Stdin:   os.Stdin,
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+		FS:      runner.OSFS{},
+		Fetch:   client.SaveInput("input"),
+		History: answers,
+	}
+	if err := runner.Run(ctx, day, os.Args[1:], env); err != nil {
 		log.Fatal(err)
 	}
-	fmt.Println("Part2 result: ", result)
 }
 
-func ReadRowsFromFile(filename string) ([][]int, error) {
-	file, err := os.Open(filename)
-	if err != nil {
-		return nil, err
+func Rows(filename string) iter.Seq2[[]int, error] {
+	return func(yield func([]int, error) bool) {
+		file, err := input.Open(filename)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer file.Close()
+
+		for row, err := range ScanRows(file) {
+			if !yield(row, err) {
+				return
+			}
+		}
 	}
-	defer file.Close()
+}
 
-	scanner := bufio.NewScanner(file)
-	rows := make([][]int, 0)
+func ScanRows(r io.Reader) iter.Seq2[[]int, error] {
+	return func(yield func([]int, error) bool) {
+		for row, err := range scanRows(r) {
+			if err == nil {
+				row = slices.Clone(row)
+			}
+			if !yield(row, err) {
+				return
+			}
+		}
+	}
+}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		row := SplitLine(line)
-		if len(row) > 0 {
-			rows = append(rows, row)
+// scanRows is ScanRows without a fresh slice per row: each row is only
+// valid until the next iteration.
+func scanRows(r io.Reader) iter.Seq2[[]int, error] {
+	return func(yield func([]int, error) bool) {
+		var buf []int
+		for line, err := range input.ByteLines(r) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			buf = parseRow(buf[:0], line.Value)
+			if len(buf) == 0 {
+				continue
+			}
+			if !yield(buf, nil) {
+				return
+			}
 		}
 	}
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
+// parseRow appends the levels on line to buf. Lines that SplitLine would
+// reject go through it, so that they are still logged and skipped.
+func parseRow(buf []int, line []byte) []int {
+	row, err := parse.AppendInts(buf, line)
+	if err != nil || slices.ContainsFunc(row, func(n int) bool { return n < 0 }) {
+		return SplitLine(string(line))
 	}
+	return row
+}
+
+func CountRows(filename string, pred func([]int) bool) (int, error) {
+	return input.FromFile(filename, func(r io.Reader) (int, error) {
+		return CountMatching(context.Background(), r, pred)
+	})
+}
 
+func CountMatching(ctx context.Context, r io.Reader, pred func([]int) bool) (int, error) {
+	count := 0
+	rows := 0
+	rep := progress.FromContext(ctx)
+	for row, err := range scanRows(r) {
+		if err != nil {
+			return 0, err
+		}
+		if err := runner.Checkpoint(ctx, rows); err != nil {
+			return 0, err
+		}
+		rows++
+		if rep != nil {
+			rep.AddRows(1)
+		}
+		if pred(row) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func ReadRowsFromFile(filename string) ([][]int, error) {
+	rows := make([][]int, 0)
+	for row, err := range Rows(filename) {
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
 	return rows, nil
 }
 
 func SplitLine(line string) []int {
-	fields := strings.Fields(line)
-
-	nums := make([]int, 0, len(fields))
-	for _, field := range fields {
-		num, err := strconv.Atoi(field)
-		if err == nil {
-			if num < 0 {
-				log.Printf("Negative number %d found in input", num)
-				return nil
-			}
-			nums = append(nums, num)
-		} else {
-			log.Printf("Error converting %s to int: %v", field, err)
+	nums, err := parse.Ints(line)
+	if err != nil {
+		log.Printf("Error converting %q to ints: %v", line, err)
+		return nil
+	}
+
+	for _, num := range nums {
+		if num < 0 {
+			log.Printf("Negative number %d found in input", num)
 			return nil
 		}
 	}
@@ -1,18 +1,20 @@
 package main
 
+import (
+	"context"
+	"io"
+
+	"adventofcode2024/input"
+)
+
 func Part1(filename string) (int, error) {
-	rows, err := ReadRowsFromFile(filename)
-	if err != nil {
-		return 0, err
-	}
+	return input.FromFile(filename, func(r io.Reader) (int, error) {
+		return Solve1(context.Background(), r)
+	})
+}
 
-	safe := 0
-	for i := 0; i < len(rows); i++ {
-		if IsSafe(rows[i]) {
-			safe++
-		}
-	}
-	return safe, nil
+func Solve1(ctx context.Context, r io.Reader) (int, error) {
+	return CountMatching(ctx, r, IsSafe)
 }
 
 func IsInOrder(row []int) bool {
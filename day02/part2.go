@@ -1,21 +1,57 @@
 package main
 
+import (
+	"context"
+	"io"
+
+	"adventofcode2024/input"
+)
+
 func Part2(filename string) (int, error) {
-	rows, err := ReadRowsFromFile(filename)
-	if err != nil {
-		return 0, err
+	return input.FromFile(filename, func(r io.Reader) (int, error) {
+		return Solve2(context.Background(), r)
+	})
+}
+
+func Solve2(ctx context.Context, r io.Reader) (int, error) {
+	return CountMatching(ctx, r, func(row []int) bool {
+		return IsSafe(row) || CanBeMadeSafe(row)
+	})
+}
+
+func CanBeMadeSafe(row []int) bool {
+	return canBeMadeSafeInDirection(row, 1) || canBeMadeSafeInDirection(row, -1)
+}
+
+func canBeMadeSafeInDirection(row []int, dir int) bool {
+	for i := 1; i < len(row); i++ {
+		if !IsSafeStep(row[i-1], row[i], dir) {
+			return IsSafeWithout(row, i-1, dir) || IsSafeWithout(row, i, dir)
+		}
 	}
+	return true
+}
+
+func IsSafeStep(from, to, dir int) bool {
+	diff := (to - from) * dir
+	return diff >= 1 && diff <= 3
+}
 
-	safe := 0
-	for i := 0; i < len(rows); i++ {
-		if IsSafe(rows[i]) || CanBeMadeSafe(rows[i]) {
-			safe++
+func IsSafeWithout(row []int, skip, dir int) bool {
+	prev := -1
+	for i := 0; i < len(row); i++ {
+		if i == skip {
+			continue
 		}
+		if prev >= 0 && !IsSafeStep(row[prev], row[i], dir) {
+			return false
+		}
+		prev = i
 	}
-	return safe, nil
+	return true
 }
 
-func CanBeMadeSafe(row []int) bool {
+func CanBeMadeSafeBruteForce(row []int) bool {
 	if len(row) <= 1 {
 		return true
 	}
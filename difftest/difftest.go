@@ -0,0 +1,82 @@
+// Package difftest runs a reference and an optimised implementation side by
+// side on random inputs and reports the first, smallest input they disagree
+// on.
+package difftest
+
+import (
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"adventofcode2024/minimize"
+)
+
+var (
+	runs = flag.Int("difftest.runs", 1000, "random inputs to try per pair")
+	seed = flag.Uint64("difftest.seed", 1, "seed for the first random input")
+)
+
+type Pair[E any, R comparable] struct {
+	Name      string
+	Reference func([]E) R
+	Fast      func([]E) R
+	Generate  func(r *rand.Rand) []E
+}
+
+type Divergence[E any, R comparable] struct {
+	Name      string
+	Seed      uint64
+	Original  []E
+	Input     []E
+	Reference R
+	Fast      R
+}
+
+func (d *Divergence[E, R]) String() string {
+	return fmt.Sprintf("%s: seed %d: input %v (shrunk from %d elements): reference = %v, fast = %v",
+		d.Name, d.Seed, d.Input, len(d.Original), d.Reference, d.Fast)
+}
+
+// Run tries n inputs, the i-th one generated from seed+i, and returns nil
+// if both implementations always agree.
+func Run[E any, R comparable](p Pair[E, R], seed uint64, n int) *Divergence[E, R] {
+	for i := 0; i < n; i++ {
+		s := seed + uint64(i)
+		input := p.Generate(rand.New(rand.NewPCG(s, s)))
+		if p.agree(input) {
+			continue
+		}
+
+		shrunk := p.shrink(input)
+		return &Divergence[E, R]{
+			Name:      p.Name,
+			Seed:      s,
+			Original:  input,
+			Input:     shrunk,
+			Reference: p.Reference(shrunk),
+			Fast:      p.Fast(shrunk),
+		}
+	}
+	return nil
+}
+
+// Check runs the pair with the -difftest.runs and -difftest.seed flags and
+// fails t on the first divergence.
+func Check[E any, R comparable](t testing.TB, p Pair[E, R]) {
+	t.Helper()
+	if d := Run(p, *seed, *runs); d != nil {
+		t.Error(d)
+	}
+}
+
+func (p Pair[E, R]) agree(input []E) bool {
+	return p.Reference(input) == p.Fast(input)
+}
+
+func (p Pair[E, R]) shrink(input []E) []E {
+	shrunk, _ := minimize.Slice(input, func(candidate []E) (bool, error) {
+		return !p.agree(candidate), nil
+	})
+	return shrunk
+}
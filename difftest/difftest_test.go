@@ -0,0 +1,87 @@
+package difftest
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func sum(nums []int) int {
+	total := 0
+	for _, num := range nums {
+		total += num
+	}
+	return total
+}
+
+// buggySum ignores every 7 it sees.
+func buggySum(nums []int) int {
+	total := 0
+	for _, num := range nums {
+		if num != 7 {
+			total += num
+		}
+	}
+	return total
+}
+
+func randomInts(r *rand.Rand) []int {
+	nums := make([]int, r.IntN(20))
+	for i := range nums {
+		nums[i] = r.IntN(10)
+	}
+	return nums
+}
+
+func TestRun_Agree(t *testing.T) {
+	p := Pair[int, int]{
+		Name:      "sum",
+		Reference: sum,
+		Fast:      sum,
+		Generate:  randomInts,
+	}
+	if d := Run(p, 1, 200); d != nil {
+		t.Errorf("unexpected divergence: %v", d)
+	}
+}
+
+func TestRun_Diverge(t *testing.T) {
+	p := Pair[int, int]{
+		Name:      "sum",
+		Reference: sum,
+		Fast:      buggySum,
+		Generate:  randomInts,
+	}
+	d := Run(p, 1, 200)
+	if d == nil {
+		t.Fatal("expected a divergence")
+	}
+	if len(d.Input) != 1 || d.Input[0] != 7 {
+		t.Errorf("expected input shrunk to [7], got %v", d.Input)
+	}
+	if d.Reference != 7 || d.Fast != 0 {
+		t.Errorf("expected results 7 and 0, got %d and %d", d.Reference, d.Fast)
+	}
+	if len(d.Original) < len(d.Input) {
+		t.Errorf("original input %v is smaller than the shrunk one", d.Original)
+	}
+
+	again := Run(p, d.Seed, 1)
+	if again == nil || again.Seed != d.Seed {
+		t.Error("rerunning from the reported seed should reproduce the divergence")
+	}
+
+	if !strings.Contains(d.String(), "sum: seed ") || !strings.Contains(d.String(), "input [7]") {
+		t.Errorf("unexpected description %q", d.String())
+	}
+}
+
+func TestCheck(t *testing.T) {
+	p := Pair[int, int]{
+		Name:      "sum",
+		Reference: sum,
+		Fast:      sum,
+		Generate:  randomInts,
+	}
+	Check(t, p)
+}
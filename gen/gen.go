@@ -0,0 +1,245 @@
+// Package gen produces random but valid puzzle inputs for load testing.
+package gen
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+)
+
+type Day01Options struct {
+	Lines int
+	Min   int
+	Max   int
+	// DuplicateRate is the probability that a right-hand value is copied
+	// from the left list, which is what makes Part2 non-zero.
+	DuplicateRate float64
+}
+
+var DefaultDay01Options = Day01Options{
+	Lines:         1000,
+	Min:           10000,
+	Max:           99999,
+	DuplicateRate: 0.1,
+}
+
+type ReportKind int
+
+const (
+	Safe ReportKind = iota
+	Dampenable
+	Unsafe
+)
+
+func (k ReportKind) String() string {
+	switch k {
+	case Safe:
+		return "safe"
+	case Dampenable:
+		return "dampenable"
+	case Unsafe:
+		return "unsafe"
+	}
+	return fmt.Sprintf("ReportKind(%d)", int(k))
+}
+
+type Day02Options struct {
+	Reports   int
+	MinLevels int
+	MaxLevels int
+	Min       int
+	Max       int
+	// SafeRate and DampenableRate are the target fractions of reports that
+	// are safe as-is and that become safe after removing one level. The
+	// remaining reports stay unsafe either way.
+	SafeRate       float64
+	DampenableRate float64
+}
+
+var DefaultDay02Options = Day02Options{
+	Reports:        1000,
+	MinLevels:      5,
+	MaxLevels:      8,
+	Min:            1,
+	Max:            99,
+	SafeRate:       0.4,
+	DampenableRate: 0.2,
+}
+
+func NewRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+}
+
+func (o Day01Options) Validate() error {
+	switch {
+	case o.Lines < 0:
+		return errors.New("lines must not be negative")
+	case o.Min > o.Max:
+		return fmt.Errorf("min %d is greater than max %d", o.Min, o.Max)
+	case o.DuplicateRate < 0 || o.DuplicateRate > 1:
+		return fmt.Errorf("duplicate rate %v is not between 0 and 1", o.DuplicateRate)
+	}
+	return nil
+}
+
+func Day01(r *rand.Rand, opts Day01Options) ([][2]int, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	pairs := make([][2]int, opts.Lines)
+	for i := range pairs {
+		pairs[i][0] = between(r, opts.Min, opts.Max)
+	}
+	for i := range pairs {
+		if r.Float64() < opts.DuplicateRate {
+			pairs[i][1] = pairs[r.IntN(len(pairs))][0]
+		} else {
+			pairs[i][1] = between(r, opts.Min, opts.Max)
+		}
+	}
+	return pairs, nil
+}
+
+func WriteDay01(w io.Writer, pairs [][2]int) error {
+	bw := bufio.NewWriter(w)
+	for _, pair := range pairs {
+		fmt.Fprintf(bw, "%d   %d\n", pair[0], pair[1])
+	}
+	return bw.Flush()
+}
+
+func (o Day02Options) Validate() error {
+	switch {
+	case o.Reports < 0:
+		return errors.New("reports must not be negative")
+	case o.Min < 0:
+		return fmt.Errorf("min %d must not be negative", o.Min)
+	case o.MinLevels < 4:
+		return fmt.Errorf("min levels %d is below 4", o.MinLevels)
+	case o.MinLevels > o.MaxLevels:
+		return fmt.Errorf("min levels %d is greater than max levels %d", o.MinLevels, o.MaxLevels)
+	case o.Max-o.Min < o.MaxLevels:
+		return fmt.Errorf("value range %d..%d is too small for %d levels", o.Min, o.Max, o.MaxLevels)
+	case o.SafeRate < 0 || o.DampenableRate < 0 || o.SafeRate+o.DampenableRate > 1:
+		return fmt.Errorf("safe rate %v and dampenable rate %v must add up to at most 1", o.SafeRate, o.DampenableRate)
+	}
+	return nil
+}
+
+func Day02(r *rand.Rand, opts Day02Options) ([][]int, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	reports := make([][]int, opts.Reports)
+	for i := range reports {
+		kind := Unsafe
+		switch p := r.Float64(); {
+		case p < opts.SafeRate:
+			kind = Safe
+		case p < opts.SafeRate+opts.DampenableRate:
+			kind = Dampenable
+		}
+		reports[i] = report(r, opts, kind)
+	}
+	return reports, nil
+}
+
+// Report returns a single report of the given kind. opts must be valid.
+func Report(r *rand.Rand, opts Day02Options, kind ReportKind) []int {
+	return report(r, opts, kind)
+}
+
+func report(r *rand.Rand, opts Day02Options, kind ReportKind) []int {
+	levels := between(r, opts.MinLevels, opts.MaxLevels)
+	switch kind {
+	case Dampenable:
+		row := safeReport(r, opts, levels-1)
+		i := r.IntN(len(row))
+		if r.IntN(2) == 0 || !insertReversal(opts, &row, i) {
+			row = insertAt(row, i, row[i])
+		}
+		return row
+	case Unsafe:
+		// Two disjoint pairs of equal neighbours: removing a single level
+		// can only ever fix one of them.
+		row := safeReport(r, opts, levels-2)
+		i := r.IntN(len(row) - 1)
+		j := i + 1 + r.IntN(len(row)-i-1)
+		row = insertAt(row, j, row[j])
+		return insertAt(row, i, row[i])
+	}
+	return safeReport(r, opts, levels)
+}
+
+func safeReport(r *rand.Rand, opts Day02Options, levels int) []int {
+	steps := make([]int, levels-1)
+	total := 0
+	for i := range steps {
+		steps[i] = 1 + r.IntN(3)
+		total += steps[i]
+	}
+	for total > opts.Max-opts.Min {
+		for i := range steps {
+			if steps[i] > 1 && total > opts.Max-opts.Min {
+				steps[i]--
+				total--
+			}
+		}
+	}
+
+	row := make([]int, levels)
+	row[0] = between(r, opts.Min, opts.Max-total)
+	for i, step := range steps {
+		row[i+1] = row[i] + step
+	}
+	if r.IntN(2) == 0 {
+		for i := range row {
+			row[i] = opts.Max + opts.Min - row[i]
+		}
+	}
+	return row
+}
+
+// insertReversal puts a level right after row[i] that steps back against
+// the direction of the report, if it fits in the value range.
+func insertReversal(opts Day02Options, row *[]int, i int) bool {
+	dir := 1
+	if (*row)[1] < (*row)[0] {
+		dir = -1
+	}
+	value := (*row)[i] - dir
+	if value < opts.Min || value > opts.Max {
+		return false
+	}
+	*row = insertAt(*row, i+1, value)
+	return true
+}
+
+func insertAt(row []int, i, value int) []int {
+	row = append(row, 0)
+	copy(row[i+1:], row[i:])
+	row[i] = value
+	return row
+}
+
+func WriteDay02(w io.Writer, reports [][]int) error {
+	bw := bufio.NewWriter(w)
+	for _, report := range reports {
+		for i, level := range report {
+			if i > 0 {
+				bw.WriteByte(' ')
+			}
+			fmt.Fprint(bw, level)
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+func between(r *rand.Rand, lo, hi int) int {
+	return lo + r.IntN(hi-lo+1)
+}
@@ -0,0 +1,190 @@
+package gen
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// isSafe and canBeMadeSafe mirror day02's rules; the day packages are
+// commands and cannot be imported from here.
+func isSafe(row []int) bool {
+	increasing, decreasing := true, true
+	for i := 1; i < len(row); i++ {
+		diff := row[i] - row[i-1]
+		if diff <= 0 || diff > 3 {
+			increasing = false
+		}
+		if diff >= 0 || diff < -3 {
+			decreasing = false
+		}
+	}
+	return increasing || decreasing
+}
+
+func canBeMadeSafe(row []int) bool {
+	for i := range row {
+		newRow := append(append([]int{}, row[:i]...), row[i+1:]...)
+		if isSafe(newRow) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDay01(t *testing.T) {
+	opts := Day01Options{Lines: 500, Min: 10, Max: 20, DuplicateRate: 0.5}
+	pairs, err := Day01(NewRand(1), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pairs) != opts.Lines {
+		t.Fatalf("got %d pairs, expected %d", len(pairs), opts.Lines)
+	}
+
+	left := make(map[int]bool)
+	for _, pair := range pairs {
+		left[pair[0]] = true
+		for _, num := range pair {
+			if num < opts.Min || num > opts.Max {
+				t.Fatalf("value %d outside %d..%d", num, opts.Min, opts.Max)
+			}
+		}
+	}
+	duplicates := 0
+	for _, pair := range pairs {
+		if left[pair[1]] {
+			duplicates++
+		}
+	}
+	if duplicates < opts.Lines/2 {
+		t.Errorf("only %d right values appear on the left", duplicates)
+	}
+}
+
+func TestDay01_Reproducible(t *testing.T) {
+	a, _ := Day01(NewRand(42), DefaultDay01Options)
+	b, _ := Day01(NewRand(42), DefaultDay01Options)
+	c, _ := Day01(NewRand(43), DefaultDay01Options)
+	if !reflect.DeepEqual(a, b) {
+		t.Error("same seed should produce the same input")
+	}
+	if reflect.DeepEqual(a, c) {
+		t.Error("different seeds should produce different inputs")
+	}
+}
+
+func TestDay02(t *testing.T) {
+	opts := DefaultDay02Options
+	opts.Reports = 2000
+	reports, err := Day02(NewRand(7), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reports) != opts.Reports {
+		t.Fatalf("got %d reports, expected %d", len(reports), opts.Reports)
+	}
+
+	safe, dampenable := 0, 0
+	for _, report := range reports {
+		if len(report) < opts.MinLevels || len(report) > opts.MaxLevels {
+			t.Fatalf("report %v has %d levels", report, len(report))
+		}
+		for _, level := range report {
+			if level < opts.Min || level > opts.Max {
+				t.Fatalf("report %v has level outside %d..%d", report, opts.Min, opts.Max)
+			}
+		}
+		switch {
+		case isSafe(report):
+			safe++
+		case canBeMadeSafe(report):
+			dampenable++
+		}
+	}
+
+	assertRate := func(name string, got int, want float64) {
+		rate := float64(got) / float64(len(reports))
+		if rate < want-0.05 || rate > want+0.05 {
+			t.Errorf("%s rate = %.3f, want about %.2f", name, rate, want)
+		}
+	}
+	assertRate("safe", safe, opts.SafeRate)
+	assertRate("dampenable", dampenable, opts.DampenableRate)
+}
+
+func TestReport(t *testing.T) {
+	opts := Day02Options{Reports: 1, MinLevels: 4, MaxLevels: 6, Min: 1, Max: 7}
+	r := NewRand(3)
+	for i := 0; i < 1000; i++ {
+		for _, kind := range []ReportKind{Safe, Dampenable, Unsafe} {
+			report := Report(r, opts, kind)
+			var got ReportKind
+			switch {
+			case isSafe(report):
+				got = Safe
+			case canBeMadeSafe(report):
+				got = Dampenable
+			default:
+				got = Unsafe
+			}
+			if got != kind {
+				t.Fatalf("Report(%v) = %v, which is %v", kind, report, got)
+			}
+		}
+	}
+}
+
+func TestOptions_Errors(t *testing.T) {
+	day01 := []Day01Options{
+		{Lines: -1, Min: 1, Max: 2},
+		{Lines: 1, Min: 3, Max: 2},
+		{Lines: 1, Min: 1, Max: 2, DuplicateRate: 1.5},
+	}
+	for _, opts := range day01 {
+		if _, err := Day01(NewRand(1), opts); err == nil {
+			t.Errorf("Day01(%+v) should error", opts)
+		}
+	}
+
+	day02 := []Day02Options{
+		{Reports: -1, MinLevels: 5, MaxLevels: 8, Min: 1, Max: 99},
+		{Reports: 1, MinLevels: 3, MaxLevels: 8, Min: 1, Max: 99},
+		{Reports: 1, MinLevels: 9, MaxLevels: 8, Min: 1, Max: 99},
+		{Reports: 1, MinLevels: 5, MaxLevels: 8, Min: 1, Max: 5},
+		{Reports: 1, MinLevels: 5, MaxLevels: 8, Min: 1, Max: 99, SafeRate: 0.8, DampenableRate: 0.3},
+		{Reports: 1, MinLevels: 5, MaxLevels: 8, Min: -5, Max: 10},
+	}
+	for _, opts := range day02 {
+		if _, err := Day02(NewRand(1), opts); err == nil {
+			t.Errorf("Day02(%+v) should error", opts)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDay01(&buf, [][2]int{{3, 4}, {10, 200}}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "3   4\n10   200\n" {
+		t.Errorf("WriteDay01() wrote %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteDay02(&buf, [][]int{{7, 6, 4}, {1}}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "7 6 4\n1\n" {
+		t.Errorf("WriteDay02() wrote %q", buf.String())
+	}
+}
+
+func TestReportKind_String(t *testing.T) {
+	names := map[ReportKind]string{Safe: "safe", Dampenable: "dampenable", Unsafe: "unsafe", 9: "ReportKind(9)"}
+	for kind, name := range names {
+		if kind.String() != name {
+			t.Errorf("String() = %q, want %q", kind.String(), name)
+		}
+	}
+}
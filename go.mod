@@ -1,3 +1,8 @@
 module adventofcode2024
 
 go 1.23.2
+
+require (
+	github.com/klauspost/compress v1.18.0
+	github.com/ulikunitz/xz v0.5.15
+)
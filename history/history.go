@@ -0,0 +1,208 @@
+// Package history keeps the answers submitted for each puzzle and what the
+// site said about them, so that an answer known to be wrong is not sent
+// again.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type Outcome string
+
+const (
+	Correct       Outcome = "correct"
+	TooHigh       Outcome = "too high"
+	TooLow        Outcome = "too low"
+	Wrong         Outcome = "wrong"
+	Wait          Outcome = "wait"
+	AlreadySolved Outcome = "already solved"
+	Unknown       Outcome = "unknown"
+)
+
+// Attempt is one submitted answer. InputHash identifies the input the
+// answer was computed from, as in runner.Result, when it is known.
+type Attempt struct {
+	Day       int       `json:"day"`
+	Part      int       `json:"part"`
+	Answer    int       `json:"answer"`
+	Outcome   Outcome   `json:"outcome"`
+	InputHash string    `json:"input_hash,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+type History struct {
+	Attempts []Attempt `json:"attempts"`
+}
+
+var (
+	ErrSolved      = errors.New("already solved")
+	ErrMismatch    = errors.New("differs from the accepted answer")
+	ErrKnownWrong  = errors.New("already rejected")
+	ErrOutOfBounds = errors.New("outside the known bounds")
+)
+
+// DefaultPath is aoc/history.json in the user's config directory, unless
+// AOC_HISTORY names another file.
+func DefaultPath() (string, error) {
+	if path := os.Getenv("AOC_HISTORY"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "aoc", "history.json"), nil
+}
+
+// LoadDefault loads the history from DefaultPath.
+func LoadDefault() (*History, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return Load(path)
+}
+
+// Load reads the history in filename. A missing file is an empty history.
+func Load(filename string) (*History, error) {
+	content, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return &History{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var h History
+	if err := json.Unmarshal(content, &h); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return &h, nil
+}
+
+// Save writes the history to filename through a temporary file, so an
+// interrupted save never leaves a truncated history behind.
+func (h *History) Save(filename string) error {
+	content, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(content, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+func (h *History) Record(a Attempt) {
+	h.Attempts = append(h.Attempts, a)
+}
+
+// For returns the attempts for one part, oldest first.
+func (h *History) For(day, part int) []Attempt {
+	var attempts []Attempt
+	for _, a := range h.Attempts {
+		if a.Day == day && a.Part == part {
+			attempts = append(attempts, a)
+		}
+	}
+	return attempts
+}
+
+// Solution returns the accepted answer for a part, if there is one.
+func (h *History) Solution(day, part int) (int, bool) {
+	return solution(h.For(day, part))
+}
+
+func solution(attempts []Attempt) (int, bool) {
+	for _, a := range attempts {
+		if a.Outcome == Correct {
+			return a.Answer, true
+		}
+	}
+	return 0, false
+}
+
+// Bounds returns the range the answer must be in, given every answer that
+// was too low or too high: the answer is above low and below high. hasLow
+// and hasHigh are false while there is no such answer.
+func (h *History) Bounds(day, part int) (low, high int, hasLow, hasHigh bool) {
+	return bounds(h.For(day, part))
+}
+
+func bounds(attempts []Attempt) (low, high int, hasLow, hasHigh bool) {
+	for _, a := range attempts {
+		switch a.Outcome {
+		case TooLow:
+			if !hasLow || a.Answer > low {
+				low, hasLow = a.Answer, true
+			}
+		case TooHigh:
+			if !hasHigh || a.Answer < high {
+				high, hasHigh = a.Answer, true
+			}
+		}
+	}
+	return low, high, hasLow, hasHigh
+}
+
+// Check reports whether answer is worth submitting. The error wraps
+// ErrSolved, ErrKnownWrong or ErrOutOfBounds.
+func (h *History) Check(day, part, answer int) error {
+	attempts := h.For(day, part)
+	if solution, ok := solution(attempts); ok {
+		return fmt.Errorf("day %d part %d: %w with %d", day, part, ErrSolved, solution)
+	}
+	return check(day, part, attempts, answer)
+}
+
+// Verify checks an answer computed from the input with the given hash
+// against the attempts made with that same input, so that runs on example
+// inputs are left alone. Once a part is solved, any other answer is an
+// ErrMismatch; before that, the error is the one Check would return.
+func (h *History) Verify(day, part int, inputHash string, answer int) error {
+	var attempts []Attempt
+	for _, a := range h.For(day, part) {
+		if a.InputHash != "" && a.InputHash == inputHash {
+			attempts = append(attempts, a)
+		}
+	}
+	if solution, ok := solution(attempts); ok {
+		if answer != solution {
+			return fmt.Errorf("day %d part %d: %d %w %d", day, part, answer, ErrMismatch, solution)
+		}
+		return nil
+	}
+	return check(day, part, attempts, answer)
+}
+
+func check(day, part int, attempts []Attempt, answer int) error {
+	for _, a := range attempts {
+		if a.Answer == answer && a.Outcome != Wait && a.Outcome != Unknown {
+			return fmt.Errorf("day %d part %d: %d was %w (%s)", day, part, answer, ErrKnownWrong, a.Outcome)
+		}
+	}
+	low, high, hasLow, hasHigh := bounds(attempts)
+	if hasLow && answer <= low {
+		return fmt.Errorf("day %d part %d: %d is %w, %d was already too low", day, part, answer, ErrOutOfBounds, low)
+	}
+	if hasHigh && answer >= high {
+		return fmt.Errorf("day %d part %d: %d is %w, %d was already too high", day, part, answer, ErrOutOfBounds, high)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func attempts(outcomes map[int]Outcome) *History {
+	h := &History{}
+	for answer, outcome := range outcomes {
+		h.Record(Attempt{Day: 1, Part: 2, Answer: answer, Outcome: outcome})
+	}
+	return h
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		history  *History
+		answer   int
+		expected error
+	}{
+		{name: "empty", history: &History{}, answer: 10},
+		{name: "known wrong", history: attempts(map[int]Outcome{10: Wrong}), answer: 10, expected: ErrKnownWrong},
+		{name: "other wrong", history: attempts(map[int]Outcome{10: Wrong}), answer: 11},
+		{name: "too high", history: attempts(map[int]Outcome{100: TooHigh}), answer: 100, expected: ErrKnownWrong},
+		{name: "above too high", history: attempts(map[int]Outcome{100: TooHigh}), answer: 150, expected: ErrOutOfBounds},
+		{name: "below too low", history: attempts(map[int]Outcome{50: TooLow}), answer: 20, expected: ErrOutOfBounds},
+		{name: "within bounds", history: attempts(map[int]Outcome{50: TooLow, 100: TooHigh, 90: TooHigh}), answer: 70},
+		{name: "tightest bound", history: attempts(map[int]Outcome{50: TooLow, 100: TooHigh, 90: TooHigh}), answer: 95, expected: ErrOutOfBounds},
+		{name: "waited", history: attempts(map[int]Outcome{10: Wait}), answer: 10},
+		{name: "solved", history: attempts(map[int]Outcome{10: Correct}), answer: 10, expected: ErrSolved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.history.Check(1, 2, tt.answer)
+			if !errors.Is(err, tt.expected) || (tt.expected == nil && err != nil) {
+				t.Errorf("Check(%d) = %v, want %v", tt.answer, err, tt.expected)
+			}
+			if err := tt.history.Check(1, 1, tt.answer); err != nil {
+				t.Errorf("the other part should not be affected, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBounds(t *testing.T) {
+	low, high, hasLow, hasHigh := attempts(map[int]Outcome{50: TooLow, 60: TooLow, 100: TooHigh, 70: Wrong}).Bounds(1, 2)
+	if low != 60 || !hasLow || high != 100 || !hasHigh {
+		t.Errorf("Bounds() = %d, %d, %t, %t", low, high, hasLow, hasHigh)
+	}
+	if _, _, hasLow, hasHigh := (&History{}).Bounds(1, 2); hasLow || hasHigh {
+		t.Error("an empty history has no bounds")
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "aoc", "history.json")
+
+	h, err := Load(filename)
+	if err != nil {
+		t.Fatalf("loading a missing file: %v", err)
+	}
+	if len(h.Attempts) != 0 {
+		t.Errorf("expected an empty history, got %v", h.Attempts)
+	}
+
+	h.Record(Attempt{Day: 2, Part: 1, Answer: 42, Outcome: TooLow, Time: time.Date(2024, 12, 2, 6, 0, 0, 0, time.UTC)})
+	h.Record(Attempt{Day: 2, Part: 1, Answer: 43, Outcome: Correct, Time: time.Date(2024, 12, 2, 6, 5, 0, 0, time.UTC)})
+	if err := h.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, h) {
+		t.Errorf("Load() = %+v, want %+v", loaded, h)
+	}
+	if answer, ok := loaded.Solution(2, 1); !ok || answer != 43 {
+		t.Errorf("Solution() = %d, %t", answer, ok)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(filename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the history file, got %v", entries)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(filename, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(filename); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	h := &History{}
+	h.Record(Attempt{Day: 1, Part: 1, Answer: 11, Outcome: Correct, InputHash: "real"})
+	h.Record(Attempt{Day: 1, Part: 2, Answer: 30, Outcome: TooLow, InputHash: "real"})
+	h.Record(Attempt{Day: 1, Part: 2, Answer: 40, Outcome: Wrong})
+
+	tests := []struct {
+		name     string
+		part     int
+		hash     string
+		answer   int
+		expected error
+	}{
+		{name: "accepted answer", part: 1, hash: "real", answer: 11},
+		{name: "other answer", part: 1, hash: "real", answer: 12, expected: ErrMismatch},
+		{name: "other input", part: 1, hash: "example", answer: 12},
+		{name: "rejected answer", part: 2, hash: "real", answer: 30, expected: ErrKnownWrong},
+		{name: "below bound", part: 2, hash: "real", answer: 20, expected: ErrOutOfBounds},
+		{name: "unknown input", part: 2, hash: "real", answer: 40},
+		{name: "no hash", part: 2, hash: "", answer: 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := h.Verify(1, tt.part, tt.hash, tt.answer)
+			if !errors.Is(err, tt.expected) || (tt.expected == nil && err != nil) {
+				t.Errorf("Verify() = %v, want %v", err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+// Package input provides iterators over line-oriented puzzle inputs.
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+
+	"adventofcode2024/parse"
+)
+
+type Record[T any] struct {
+	Line  int
+	Value T
+}
+
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+type LineTooLongError struct {
+	Line  int
+	Limit int
+}
+
+func (e *LineTooLongError) Error() string {
+	return fmt.Sprintf("line %d: longer than the %d byte limit", e.Line, e.Limit)
+}
+
+func (e *LineTooLongError) Unwrap() error {
+	return bufio.ErrTooLong
+}
+
+const defaultBufferSize = 64 * 1024
+
+type config struct {
+	bufferSize    int
+	maxLineLength int
+}
+
+type Option func(*config)
+
+// WithBufferSize sets the size of the read buffer. Lines longer than the
+// buffer are still read, just in several chunks.
+func WithBufferSize(n int) Option {
+	return func(c *config) {
+		c.bufferSize = n
+	}
+}
+
+// WithMaxLineLength makes iteration stop with a *LineTooLongError when a
+// line is longer than n bytes. By default lines can be of any length.
+func WithMaxLineLength(n int) Option {
+	return func(c *config) {
+		c.maxLineLength = n
+	}
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{bufferSize: defaultBufferSize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+func Lines(r io.Reader, opts ...Option) iter.Seq2[Record[string], error] {
+	return func(yield func(Record[string], error) bool) {
+		for rec, err := range ByteLines(r, opts...) {
+			if !yield(Record[string]{Line: rec.Line, Value: string(rec.Value)}, err) {
+				return
+			}
+		}
+	}
+}
+
+// ByteLines is Lines without the copy into a string. Value points into the
+// read buffer and is only valid until the next iteration; callers that
+// keep it must copy it, and none may modify it.
+//
+// A Buffered reader, such as a file mapped by Open, is read in place.
+func ByteLines(r io.Reader, opts ...Option) iter.Seq2[Record[[]byte], error] {
+	cfg := newConfig(opts)
+	return func(yield func(Record[[]byte], error) bool) {
+		var next func() ([]byte, error)
+		if b, ok := r.(Buffered); ok {
+			next = func() ([]byte, error) {
+				return nextLine(b, cfg.maxLineLength)
+			}
+		} else {
+			reader := bufio.NewReaderSize(r, cfg.bufferSize)
+			var long []byte
+			next = func() ([]byte, error) {
+				return readLine(reader, cfg.maxLineLength, &long)
+			}
+		}
+
+		line := 0
+		for {
+			text, err := next()
+			if text == nil && err == io.EOF {
+				return
+			}
+			line++
+			if err == bufio.ErrTooLong {
+				yield(Record[[]byte]{Line: line}, &LineTooLongError{Line: line, Limit: cfg.maxLineLength})
+				return
+			}
+			if err != nil && err != io.EOF {
+				yield(Record[[]byte]{Line: line}, err)
+				return
+			}
+			if !yield(Record[[]byte]{Line: line, Value: text}, nil) {
+				return
+			}
+			if err == io.EOF {
+				return
+			}
+		}
+	}
+}
+
+// Buffered is implemented by readers that already hold the rest of the
+// input in memory, like *bytes.Buffer: Bytes returns the unread part and
+// Next consumes n bytes of it.
+type Buffered interface {
+	io.Reader
+	Bytes() []byte
+	Next(n int) []byte
+}
+
+// nextLine is readLine for a Buffered reader.
+func nextLine(b Buffered, limit int) ([]byte, error) {
+	rest := b.Bytes()
+	n := len(rest)
+	if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+		n = i + 1
+	}
+	chunk := b.Next(n)
+	var err error
+	if n == 0 || chunk[n-1] != '\n' {
+		err = io.EOF
+	}
+	if limit > 0 && len(bytes.TrimRight(chunk, "\r\n")) > limit {
+		return nil, bufio.ErrTooLong
+	}
+	return finishLine(chunk, err)
+}
+
+// readLine returns the next line without its terminator. A final line with
+// no trailing newline comes back together with io.EOF; a nil line with
+// io.EOF means there is nothing left to read.
+//
+// A line that fits in the reader's buffer is returned in place. Longer ones
+// are put together in *long, which is reused from one line to the next.
+func readLine(r *bufio.Reader, limit int, long *[]byte) ([]byte, error) {
+	chunk, err := r.ReadSlice('\n')
+	if err != bufio.ErrBufferFull {
+		if limit > 0 && len(bytes.TrimRight(chunk, "\r\n")) > limit {
+			return nil, bufio.ErrTooLong
+		}
+		return finishLine(chunk, err)
+	}
+
+	line := append((*long)[:0], chunk...)
+	defer func() { *long = line[:0] }()
+	for {
+		if limit > 0 && len(bytes.TrimRight(line, "\r\n")) > limit {
+			return nil, bufio.ErrTooLong
+		}
+		if err != bufio.ErrBufferFull {
+			return finishLine(line, err)
+		}
+		chunk, err = r.ReadSlice('\n')
+		line = append(line, chunk...)
+	}
+}
+
+func finishLine(line []byte, err error) ([]byte, error) {
+	switch err {
+	case nil:
+		return dropTerminator(line), nil
+	case io.EOF:
+		if len(line) == 0 {
+			return nil, io.EOF
+		}
+		return dropTerminator(line), io.EOF
+	default:
+		return nil, err
+	}
+}
+
+func dropTerminator(line []byte) []byte {
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if line == nil {
+		return []byte{}
+	}
+	return line
+}
+
+func Fields(r io.Reader, opts ...Option) iter.Seq2[Record[[]string], error] {
+	return mapLines(r, opts, func(text string) ([]string, bool, error) {
+		fields := strings.Fields(text)
+		return fields, len(fields) > 0, nil
+	})
+}
+
+func Ints(r io.Reader, opts ...Option) iter.Seq2[Record[int], error] {
+	return mapLines(r, opts, func(text string) (int, bool, error) {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return 0, false, nil
+		}
+		num, err := parse.Int(text)
+		return num, true, err
+	})
+}
+
+func IntRows(r io.Reader, opts ...Option) iter.Seq2[Record[[]int], error] {
+	return mapLines(r, opts, func(text string) ([]int, bool, error) {
+		nums, err := parse.Ints(text)
+		return nums, len(nums) > 0 || err != nil, err
+	})
+}
+
+func Pairs(r io.Reader, opts ...Option) iter.Seq2[Record[[2]int], error] {
+	return mapLines(r, opts, func(text string) ([2]int, bool, error) {
+		var pair [2]int
+		if strings.TrimSpace(text) == "" {
+			return pair, false, nil
+		}
+		nums, err := parse.Tuple(text, 2)
+		if err != nil {
+			return pair, true, err
+		}
+		copy(pair[:], nums)
+		return pair, true, nil
+	})
+}
+
+// mapLines parses every line with fn. Lines for which fn reports ok=false
+// are skipped; parse errors are yielded as *ParseError and iteration goes
+// on for as long as the caller keeps ranging.
+func mapLines[T any](r io.Reader, opts []Option, fn func(string) (T, bool, error)) iter.Seq2[Record[T], error] {
+	return func(yield func(Record[T], error) bool) {
+		for rec, err := range Lines(r, opts...) {
+			if err != nil {
+				yield(Record[T]{Line: rec.Line}, err)
+				return
+			}
+			value, ok, err := fn(rec.Value)
+			if err != nil {
+				err = &ParseError{Line: rec.Line, Err: err}
+			} else if !ok {
+				continue
+			}
+			if !yield(Record[T]{Line: rec.Line, Value: value}, err) {
+				return
+			}
+		}
+	}
+}
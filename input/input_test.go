@@ -0,0 +1,347 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Record[string]
+	}{
+		{
+			name:     "empty input",
+			input:    "",
+			expected: []Record[string]{},
+		},
+		{
+			name:  "multiple lines",
+			input: "a\nb b\n\nc",
+			expected: []Record[string]{
+				{Line: 1, Value: "a"},
+				{Line: 2, Value: "b b"},
+				{Line: 3, Value: ""},
+				{Line: 4, Value: "c"},
+			},
+		},
+		{
+			name:  "trailing newline",
+			input: "a\r\nb\n",
+			expected: []Record[string]{
+				{Line: 1, Value: "a"},
+				{Line: 2, Value: "b"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := make([]Record[string], 0)
+			for rec, err := range Lines(strings.NewReader(tt.input)) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				result = append(result, rec)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Lines(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLines_Error(t *testing.T) {
+	count := 0
+	for _, err := range Lines(errReader{}) {
+		count++
+		if err == nil {
+			t.Error("should propagate read errors")
+		}
+	}
+	if count != 1 {
+		t.Errorf("Lines() yielded %d times, expected 1", count)
+	}
+}
+
+func TestLines_Break(t *testing.T) {
+	count := 0
+	for range Lines(strings.NewReader("1\n2\n3")) {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("Lines() yielded %d times after break, expected 1", count)
+	}
+}
+
+func TestLines_LongLines(t *testing.T) {
+	long := strings.Repeat("1 ", 500000)
+	content := "short\n" + long + "\n" + long
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "default buffer", opts: nil},
+		{name: "tiny buffer", opts: []Option{WithBufferSize(16)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lengths := make([]int, 0)
+			for rec, err := range Lines(strings.NewReader(content), tt.opts...) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				lengths = append(lengths, len(rec.Value))
+			}
+			expected := []int{5, len(long), len(long)}
+			if !reflect.DeepEqual(lengths, expected) {
+				t.Errorf("line lengths = %v, want %v", lengths, expected)
+			}
+		})
+	}
+}
+
+func TestLines_MaxLineLength(t *testing.T) {
+	content := "1234\n12345\r\n123456\n1"
+
+	lines := make([]string, 0)
+	var lastErr error
+	for rec, err := range Lines(strings.NewReader(content), WithMaxLineLength(5), WithBufferSize(16)) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		lines = append(lines, rec.Value)
+	}
+
+	if !reflect.DeepEqual(lines, []string{"1234", "12345"}) {
+		t.Errorf("lines before error = %q", lines)
+	}
+
+	var tooLong *LineTooLongError
+	if !errors.As(lastErr, &tooLong) {
+		t.Fatalf("expected *LineTooLongError, got %v", lastErr)
+	}
+	if tooLong.Line != 3 || tooLong.Limit != 5 {
+		t.Errorf("error = %+v, want line 3 and limit 5", tooLong)
+	}
+	if !errors.Is(lastErr, bufio.ErrTooLong) {
+		t.Error("error should wrap bufio.ErrTooLong")
+	}
+	if lastErr.Error() != "line 3: longer than the 5 byte limit" {
+		t.Errorf("unexpected message %q", lastErr.Error())
+	}
+}
+
+func TestByteLines_Buffered(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		opts    []Option
+	}{
+		{name: "empty input", content: ""},
+		{name: "multiple lines", content: "a\nb b\n\nc"},
+		{name: "trailing newline", content: "a\r\nb\n"},
+		{name: "blank lines", content: "\n\n"},
+		{name: "max line length", content: "1234\n12345\r\n123456\n1", opts: []Option{WithMaxLineLength(5)}},
+		{name: "long last line", content: "1\n123456", opts: []Option{WithMaxLineLength(5)}},
+	}
+
+	type line struct {
+		rec Record[string]
+		err string
+	}
+	collect := func(r io.Reader, opts []Option) []line {
+		lines := make([]line, 0)
+		for rec, err := range ByteLines(r, opts...) {
+			l := line{rec: Record[string]{Line: rec.Line, Value: string(rec.Value)}}
+			if err != nil {
+				l.err = err.Error()
+			}
+			lines = append(lines, l)
+		}
+		return lines
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := collect(strings.NewReader(tt.content), tt.opts)
+			result := collect(bytes.NewBufferString(tt.content), tt.opts)
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("ByteLines(%q) on a Buffered reader = %v, want %v", tt.content, result, expected)
+			}
+		})
+	}
+}
+
+func TestByteLines_BufferedBreak(t *testing.T) {
+	buf := bytes.NewBufferString("1\n2\n3")
+	for range ByteLines(buf) {
+		break
+	}
+	if buf.String() != "2\n3" {
+		t.Errorf("left %q after reading one line, want %q", buf.String(), "2\n3")
+	}
+}
+
+func TestFields(t *testing.T) {
+	result := make([]Record[[]string], 0)
+	for rec, err := range Fields(strings.NewReader("a b\n\n  c\td  ")) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result = append(result, rec)
+	}
+
+	expected := []Record[[]string]{
+		{Line: 1, Value: []string{"a", "b"}},
+		{Line: 3, Value: []string{"c", "d"}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Fields() = %v, want %v", result, expected)
+	}
+}
+
+func TestInts(t *testing.T) {
+	result := make([]Record[int], 0)
+	for rec, err := range Ints(strings.NewReader("1\n -2 \n\n30")) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result = append(result, rec)
+	}
+
+	expected := []Record[int]{
+		{Line: 1, Value: 1},
+		{Line: 2, Value: -2},
+		{Line: 4, Value: 30},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Ints() = %v, want %v", result, expected)
+	}
+}
+
+func TestIntRows(t *testing.T) {
+	result := make([]Record[[]int], 0)
+	for rec, err := range IntRows(strings.NewReader("1 2 3\n\n4 -5")) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result = append(result, rec)
+	}
+
+	expected := []Record[[]int]{
+		{Line: 1, Value: []int{1, 2, 3}},
+		{Line: 3, Value: []int{4, -5}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("IntRows() = %v, want %v", result, expected)
+	}
+}
+
+func TestPairs(t *testing.T) {
+	result := make([]Record[[2]int], 0)
+	for rec, err := range Pairs(strings.NewReader("3   4\n4\t3\n")) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result = append(result, rec)
+	}
+
+	expected := []Record[[2]int]{
+		{Line: 1, Value: [2]int{3, 4}},
+		{Line: 2, Value: [2]int{4, 3}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Pairs() = %v, want %v", result, expected)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		collect  func(string) []error
+		input    string
+		expected []int
+	}{
+		{
+			name: "ints with invalid line",
+			collect: func(s string) []error {
+				errs := make([]error, 0)
+				for _, err := range Ints(strings.NewReader(s)) {
+					errs = append(errs, err)
+				}
+				return errs
+			},
+			input:    "1\nabc\n3",
+			expected: []int{0, 2, 0},
+		},
+		{
+			name: "int rows with invalid field",
+			collect: func(s string) []error {
+				errs := make([]error, 0)
+				for _, err := range IntRows(strings.NewReader(s)) {
+					errs = append(errs, err)
+				}
+				return errs
+			},
+			input:    "1 2\n3 x",
+			expected: []int{0, 2},
+		},
+		{
+			name: "pairs with wrong field count",
+			collect: func(s string) []error {
+				errs := make([]error, 0)
+				for _, err := range Pairs(strings.NewReader(s)) {
+					errs = append(errs, err)
+				}
+				return errs
+			},
+			input:    "1\n1 2\n1 2 3\na b",
+			expected: []int{1, 0, 3, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.collect(tt.input)
+			if len(errs) != len(tt.expected) {
+				t.Fatalf("got %d records, expected %d", len(errs), len(tt.expected))
+			}
+			for i, line := range tt.expected {
+				if line == 0 {
+					if errs[i] != nil {
+						t.Errorf("record %d: unexpected error: %v", i, errs[i])
+					}
+					continue
+				}
+				var perr *ParseError
+				if !errors.As(errs[i], &perr) {
+					t.Fatalf("record %d: expected *ParseError, got %v", i, errs[i])
+				}
+				if perr.Line != line {
+					t.Errorf("record %d: error on line %d, expected %d", i, perr.Line, line)
+				}
+				if !strings.HasPrefix(perr.Error(), "line "+strconv.Itoa(line)+": ") {
+					t.Errorf("record %d: unexpected message %q", i, perr.Error())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package input
+
+import (
+	"errors"
+	"math"
+	"os"
+	"syscall"
+)
+
+// mapFile maps the whole of file read-only. The mapping outlives the file,
+// which can be closed right away. Anything that is not a regular, non-empty
+// file is left to regular reads.
+func mapFile(file *os.File) ([]byte, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if !info.Mode().IsRegular() || size == 0 || size > math.MaxInt {
+		return nil, errors.ErrUnsupported
+	}
+	return syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+func unmapFile(data []byte) error {
+	return syscall.Munmap(data)
+}
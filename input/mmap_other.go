@@ -0,0 +1,16 @@
+//go:build !linux
+
+package input
+
+import (
+	"errors"
+	"os"
+)
+
+func mapFile(*os.File) ([]byte, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func unmapFile([]byte) error {
+	return nil
+}
@@ -0,0 +1,215 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	xzMagic    = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+)
+
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (rc *readCloser) Close() error {
+	var errs []error
+	for _, c := range rc.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Open opens a puzzle input file, transparently decompressing it when it
+// starts with a gzip, bzip2, zstd or xz header.
+//
+// On Linux the file is memory-mapped instead of read, and an uncompressed
+// one comes back as a Buffered reader over the mapping. Its bytes are only
+// valid until it is closed. Where mapping is not possible Open falls back
+// to reading the file.
+//
+// Truncating a mapped file makes reads past the new end fault and crash
+// the process, so inputs should be rewritten with Replace.
+func Open(filename string) (io.ReadCloser, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var src io.Reader = file
+	closer := io.Closer(file)
+	if m, err := Map(file); err == nil {
+		file.Close()
+		if !compressed(m.Bytes()) {
+			return m, nil
+		}
+		src, closer = m, m
+	}
+
+	rc, err := newReader(src)
+	if err != nil {
+		closer.Close()
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	rc.closers = append(rc.closers, closer)
+	return rc, nil
+}
+
+// Mapping is a Buffered reader over a memory-mapped file.
+type Mapping struct {
+	data []byte
+	off  int
+}
+
+// Map maps file into memory for reading. The mapping outlives file, which
+// can be closed right away, and is released by closing the Mapping. Where
+// mapping is not possible, as for empty files, devices and on systems other
+// than Linux, Map fails and the file should be read instead.
+func Map(file *os.File) (*Mapping, error) {
+	data, err := mapFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return &Mapping{data: data}, nil
+}
+
+func (m *Mapping) Read(p []byte) (int, error) {
+	if m.off >= len(m.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, m.data[m.off:])
+	m.off += n
+	return n, nil
+}
+
+func (m *Mapping) Bytes() []byte {
+	return m.data[m.off:]
+}
+
+func (m *Mapping) Next(n int) []byte {
+	n = min(n, len(m.data)-m.off)
+	b := m.data[m.off : m.off+n]
+	m.off += n
+	return b
+}
+
+func (m *Mapping) Close() error {
+	if m.data == nil {
+		return nil
+	}
+	data := m.data
+	m.data, m.off = nil, 0
+	return unmapFile(data)
+}
+
+// FromFile opens filename with Open and hands the contents to fn.
+func FromFile[T any](filename string, fn func(io.Reader) (T, error)) (T, error) {
+	rc, err := Open(filename)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	defer rc.Close()
+
+	return fn(rc)
+}
+
+// Replace writes a new version of filename through write, into a temporary
+// file that is then renamed over it. Readers that still have the old file
+// open or mapped keep their content, where truncating it in place would
+// make a mapped read fault. The file is only replaced if write succeeds.
+func Replace(filename string, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+// NewReader sniffs the first bytes of r and wraps it in the matching
+// decompressor. Closing the result does not close r. A Buffered r that is
+// not compressed comes back as a Buffered reader, so it is still read in
+// place.
+func NewReader(r io.Reader) (io.ReadCloser, error) {
+	if b, ok := r.(Buffered); ok && !compressed(b.Bytes()) {
+		return nopCloser{b}, nil
+	}
+	return newReader(r)
+}
+
+type nopCloser struct {
+	Buffered
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+func compressed(header []byte) bool {
+	for _, magic := range [][]byte{gzipMagic, bzip2Magic, zstdMagic, xzMagic} {
+		if bytes.HasPrefix(header, magic) {
+			return true
+		}
+	}
+	return false
+}
+
+func newReader(r io.Reader) (*readCloser, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(len(xzMagic))
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return &readCloser{Reader: zr, closers: []io.Closer{zr}}, nil
+	case bytes.HasPrefix(header, bzip2Magic):
+		return &readCloser{Reader: bzip2.NewReader(br)}, nil
+	case bytes.HasPrefix(header, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("zstd: %w", err)
+		}
+		rc := zr.IOReadCloser()
+		return &readCloser{Reader: rc, closers: []io.Closer{rc}}, nil
+	case bytes.HasPrefix(header, xzMagic):
+		xr, err := xz.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("xz: %w", err)
+		}
+		return &readCloser{Reader: xr}, nil
+	}
+	return &readCloser{Reader: br}, nil
+}
@@ -0,0 +1,276 @@
+package input
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestOpen(t *testing.T) {
+	expected, err := os.ReadFile(filepath.Join("testdata", "pairs.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"pairs.txt", "pairs.txt.gz", "pairs.txt.bz2", "pairs.txt.zst", "pairs.txt.xz"} {
+		t.Run(name, func(t *testing.T) {
+			rc, err := Open(filepath.Join("testdata", name))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer rc.Close()
+
+			content, err := io.ReadAll(rc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(content, expected) {
+				t.Errorf("Open(%s) read %q, want %q", name, content, expected)
+			}
+		})
+	}
+}
+
+func TestOpen_Errors(t *testing.T) {
+	if _, err := Open("nonexistentfile.txt"); err == nil {
+		t.Error("should error on nonexistent file")
+	}
+
+	// Corrupt streams fail either when opened or when read.
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{name: "gz", content: []byte{0x1f, 0x8b, 0x08, 0x00, 0x00}},
+		{name: "zst", content: []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, 0x00}},
+		{name: "xz", content: []byte{0xfd, '7', 'z', 'X', 'Z', 0x00, 0x00}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "input."+tt.name)
+			if err := os.WriteFile(filename, tt.content, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			rc, err := Open(filename)
+			if err == nil {
+				_, err = io.ReadAll(rc)
+				rc.Close()
+			}
+			if err == nil {
+				t.Error("should error on a corrupt stream")
+			}
+		})
+	}
+}
+
+func TestOpen_Mapped(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("files are only mapped on Linux")
+	}
+	expected, err := os.ReadFile(filepath.Join("testdata", "pairs.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := Open(filepath.Join("testdata", "pairs.txt"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rc.Close()
+
+	b, ok := rc.(Buffered)
+	if !ok {
+		t.Fatalf("Open() returned %T, want a Buffered reader", rc)
+	}
+	if !bytes.Equal(b.Bytes(), expected) {
+		t.Errorf("Bytes() = %q, want %q", b.Bytes(), expected)
+	}
+	if next := b.Next(4); string(next) != "3   " {
+		t.Errorf("Next(4) = %q, want %q", next, "3   ")
+	}
+	rest, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(rest, expected[4:]) {
+		t.Errorf("read %q after Next, want %q", rest, expected[4:])
+	}
+	if len(b.Next(1)) != 0 || len(b.Bytes()) != 0 {
+		t.Error("nothing should be left after reading to the end")
+	}
+	if err := rc.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if err := rc.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+
+	// Compressed files are mapped too, but read through the decompressor.
+	gz, err := Open(filepath.Join("testdata", "pairs.txt.gz"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer gz.Close()
+	if _, ok := gz.(Buffered); ok {
+		t.Error("a compressed file should not be Buffered")
+	}
+}
+
+func TestOpen_Unmappable(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename func(t *testing.T) string
+	}{
+		{
+			name: "empty file",
+			filename: func(t *testing.T) string {
+				filename := filepath.Join(t.TempDir(), "input")
+				if err := os.WriteFile(filename, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return filename
+			},
+		},
+		{
+			name: "device",
+			filename: func(t *testing.T) string {
+				if _, err := os.Stat(os.DevNull); err != nil {
+					t.Skip(err)
+				}
+				return os.DevNull
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc, err := Open(tt.filename(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer rc.Close()
+
+			content, err := io.ReadAll(rc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(content) != 0 {
+				t.Errorf("read %q, want nothing", content)
+			}
+		})
+	}
+}
+
+func TestReplace(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "input")
+	if err := os.WriteFile(filename, []byte("1 2\n3 4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old, err := Open(filename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer old.Close()
+
+	if err := Replace(filename, func(w io.Writer) error {
+		_, err := io.WriteString(w, "5\n")
+		return err
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The open file, mapped or not, still reads the old content in full.
+	content, err := io.ReadAll(old)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(content) != "1 2\n3 4\n" {
+		t.Errorf("old file read %q after Replace", content)
+	}
+
+	content, err = os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "5\n" {
+		t.Errorf("Replace() wrote %q", content)
+	}
+	if info, err := os.Stat(filename); err != nil {
+		t.Fatal(err)
+	} else if runtime.GOOS != "windows" && info.Mode().Perm() != 0644 {
+		t.Errorf("Replace() left mode %v, want 0644", info.Mode().Perm())
+	}
+
+	failed := errors.New("write failed")
+	if err := Replace(filename, func(io.Writer) error { return failed }); !errors.Is(err, failed) {
+		t.Errorf("expected the write error, got %v", err)
+	}
+	if content, _ := os.ReadFile(filename); string(content) != "5\n" {
+		t.Errorf("a failed Replace() changed the file to %q", content)
+	}
+	entries, err := os.ReadDir(filepath.Dir(filename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "empty", content: ""},
+		{name: "shorter than any magic", content: "1"},
+		{name: "plain text", content: "1 2\n3 4\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc, err := NewReader(bytes.NewReader([]byte(tt.content)))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer rc.Close()
+
+			content, err := io.ReadAll(rc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(content) != tt.content {
+				t.Errorf("NewReader() read %q, want %q", content, tt.content)
+			}
+		})
+	}
+
+	if _, err := NewReader(bytes.NewReader([]byte{0x1f, 0x8b, 0x00})); err == nil {
+		t.Error("should error on a truncated gzip header")
+	}
+}
+
+func TestFromFile(t *testing.T) {
+	length, err := FromFile(filepath.Join("testdata", "pairs.txt.gz"), func(r io.Reader) (int, error) {
+		content, err := io.ReadAll(r)
+		return len(content), err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if length != len("3   4\n4   3\n2   5\n") {
+		t.Errorf("FromFile() read %d bytes", length)
+	}
+
+	if _, err := FromFile("nonexistentfile.txt", func(io.Reader) (int, error) {
+		t.Error("fn should not be called when the file cannot be opened")
+		return 0, nil
+	}); err == nil {
+		t.Error("should error on nonexistent file")
+	}
+}
@@ -0,0 +1,96 @@
+// Package minimize shrinks failing inputs with delta debugging (ddmin).
+package minimize
+
+import (
+	"io"
+	"strings"
+
+	"adventofcode2024/input"
+)
+
+// Slice returns a subset of items, in the original order, for which test
+// still reports true and from which no single element can be removed
+// without test turning false. test must report true for items itself.
+func Slice[T any](items []T, test func([]T) (bool, error)) ([]T, error) {
+	n := 2
+	for len(items) >= 2 {
+		chunks := split(items, n)
+		reduced := false
+
+		for _, chunk := range chunks {
+			ok, err := test(chunk)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				items, n, reduced = chunk, 2, true
+				break
+			}
+		}
+
+		if !reduced && n > 2 {
+			for i := range chunks {
+				complement := make([]T, 0, len(items))
+				for j, chunk := range chunks {
+					if j != i {
+						complement = append(complement, chunk...)
+					}
+				}
+				ok, err := test(complement)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					items, n, reduced = complement, max(n-1, 2), true
+					break
+				}
+			}
+		}
+
+		if !reduced {
+			if n >= len(items) {
+				break
+			}
+			n = min(2*n, len(items))
+		}
+	}
+	return items, nil
+}
+
+func split[T any](items []T, n int) [][]T {
+	chunks := make([][]T, 0, n)
+	start := 0
+	for i := 0; i < n; i++ {
+		end := start + (len(items)-start)/(n-i)
+		chunks = append(chunks, items[start:end])
+		start = end
+	}
+	return chunks
+}
+
+// Lines minimises a line-oriented input. test receives the candidate input
+// as text, one line per input line and each one newline-terminated.
+func Lines(r io.Reader, test func(string) (bool, error)) (string, error) {
+	lines := make([]string, 0)
+	for line, err := range input.Lines(r) {
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, line.Value)
+	}
+
+	result, err := Slice(lines, func(candidate []string) (bool, error) {
+		return test(join(candidate))
+	})
+	if err != nil {
+		return "", err
+	}
+	return join(result), nil
+}
+
+func join(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,121 @@
+package minimize
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func contains(needles ...int) func([]int) (bool, error) {
+	return func(items []int) (bool, error) {
+		for _, needle := range needles {
+			if !slices.Contains(items, needle) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+}
+
+func TestSlice(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []int
+		test     func([]int) (bool, error)
+		expected []int
+	}{
+		{
+			name:     "single culprit",
+			items:    []int{1, 2, 3, 4, 5, 6, 7, 8},
+			test:     contains(6),
+			expected: []int{6},
+		},
+		{
+			name:     "two culprits far apart",
+			items:    []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+			test:     contains(2, 10),
+			expected: []int{2, 10},
+		},
+		{
+			name:     "three culprits keep their order",
+			items:    []int{9, 8, 7, 6, 5, 4, 3, 2, 1},
+			test:     contains(1, 5, 8),
+			expected: []int{8, 5, 1},
+		},
+		{
+			name:  "needs an adjacent pair",
+			items: []int{5, 1, 2, 3, 4},
+			test: func(items []int) (bool, error) {
+				for i := 1; i < len(items); i++ {
+					if items[i] < items[i-1] {
+						return true, nil
+					}
+				}
+				return false, nil
+			},
+			expected: []int{5, 1},
+		},
+		{
+			name:     "already minimal",
+			items:    []int{4},
+			test:     contains(4),
+			expected: []int{4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Slice(tt.items, tt.test)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Slice(%v) = %v, want %v", tt.items, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSlice_Error(t *testing.T) {
+	failure := errors.New("boom")
+	_, err := Slice([]int{1, 2, 3}, func([]int) (bool, error) {
+		return false, failure
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("expected the test error, got %v", err)
+	}
+}
+
+func TestLines(t *testing.T) {
+	content := "7 6 4 2 1\n1 2 7 8 9\n9 7 6 2 1\n1 3 2 4 5\n8 6 4 4 1\n1 3 6 7 9"
+	calls := 0
+	result, err := Lines(strings.NewReader(content), func(candidate string) (bool, error) {
+		calls++
+		return strings.Contains(candidate, "4 4") && strings.Contains(candidate, "2 7"), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "1 2 7 8 9\n8 6 4 4 1\n"
+	if result != expected {
+		t.Errorf("Lines() = %q, want %q", result, expected)
+	}
+	if calls == 0 {
+		t.Error("test was never called")
+	}
+}
+
+func TestLines_Empty(t *testing.T) {
+	result, err := Lines(strings.NewReader(""), func(string) (bool, error) {
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "" {
+		t.Errorf("Lines() = %q, want empty", result)
+	}
+}
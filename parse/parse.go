@@ -0,0 +1,161 @@
+// Package parse holds the small parsing helpers shared by the day packages.
+package parse
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var intPattern = regexp.MustCompile(`[-+]?\d+`)
+
+func Int(s string) (int, error) {
+	return strconv.Atoi(strings.TrimSpace(s))
+}
+
+func Ints(s string) ([]int, error) {
+	fields := strings.Fields(s)
+	nums := make([]int, len(fields))
+	for i, field := range fields {
+		num, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("field %d: %w", i+1, err)
+		}
+		nums[i] = num
+	}
+	return nums, nil
+}
+
+// AppendInts is Ints for the hot loops over large inputs: it reads the
+// line as bytes and appends to dst, so it allocates nothing beyond growing
+// dst. Only ASCII whitespace separates fields, which is where it differs
+// from Ints; the errors are the same otherwise.
+func AppendInts(dst []int, line []byte) ([]int, error) {
+	field := 0
+	for i := 0; i < len(line); {
+		if isSpace(line[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(line) && !isSpace(line[i]) {
+			i++
+		}
+		field++
+		num, err := atoi(line[start:i])
+		if err != nil {
+			return dst, fmt.Errorf("field %d: %w", field, err)
+		}
+		dst = append(dst, num)
+	}
+	return dst, nil
+}
+
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+	return false
+}
+
+// atoi is strconv.Atoi on bytes.
+func atoi(b []byte) (int, error) {
+	digits := b
+	negative := false
+	if len(digits) > 0 && (digits[0] == '-' || digits[0] == '+') {
+		negative = digits[0] == '-'
+		digits = digits[1:]
+	}
+	if len(digits) == 0 {
+		return 0, &strconv.NumError{Func: "Atoi", Num: string(b), Err: strconv.ErrSyntax}
+	}
+
+	// Errors follow strconv.Atoi: digits are read as an unsigned number and
+	// a bad byte is a syntax error until that number overflows, after which
+	// the result is out of range whatever follows.
+	maxVal := uint64(math.MaxUint)
+	var n uint64
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return 0, &strconv.NumError{Func: "Atoi", Num: string(b), Err: strconv.ErrSyntax}
+		}
+		if n > maxVal/10 {
+			return 0, &strconv.NumError{Func: "Atoi", Num: string(b), Err: strconv.ErrRange}
+		}
+		n1 := n*10 + uint64(c-'0')
+		if n1 < n*10 || n1 > maxVal {
+			return 0, &strconv.NumError{Func: "Atoi", Num: string(b), Err: strconv.ErrRange}
+		}
+		n = n1
+	}
+	// The magnitude of the most negative int; positive numbers stop one
+	// short of it.
+	limit := uint64(1) << (strconv.IntSize - 1)
+	if n > limit || (!negative && n == limit) {
+		return 0, &strconv.NumError{Func: "Atoi", Num: string(b), Err: strconv.ErrRange}
+	}
+	if negative {
+		return int(-n), nil
+	}
+	return int(n), nil
+}
+
+func Tuple(s string, n int) ([]int, error) {
+	nums, err := Ints(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(nums) != n {
+		return nil, fmt.Errorf("expected %d fields, got %d", n, len(nums))
+	}
+	return nums, nil
+}
+
+func AllInts(s string) []int {
+	matches := intPattern.FindAllString(s, -1)
+	nums := make([]int, 0, len(matches))
+	for _, match := range matches {
+		num, err := strconv.Atoi(match)
+		if err != nil {
+			continue
+		}
+		nums = append(nums, num)
+	}
+	return nums
+}
+
+func Grid(lines []string) ([][]byte, error) {
+	grid := make([][]byte, 0, len(lines))
+	for i, line := range lines {
+		if line == "" && i == len(lines)-1 {
+			break
+		}
+		if len(grid) > 0 && len(line) != len(grid[0]) {
+			return nil, fmt.Errorf("row %d: expected width %d, got %d", i+1, len(grid[0]), len(line))
+		}
+		grid = append(grid, []byte(line))
+	}
+	return grid, nil
+}
+
+func Blocks(s string) [][]string {
+	blocks := make([][]string, 0)
+	block := make([]string, 0)
+	for _, line := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(block) > 0 {
+				blocks = append(blocks, block)
+				block = make([]string, 0)
+			}
+			continue
+		}
+		block = append(block, line)
+	}
+	if len(block) > 0 {
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
@@ -0,0 +1,316 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestInt(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    int
+		expectError bool
+	}{
+		{name: "positive", input: "42", expected: 42},
+		{name: "negative", input: "-7", expected: -7},
+		{name: "explicit sign", input: "+3", expected: 3},
+		{name: "surrounding space", input: "  12\t", expected: 12},
+		{name: "empty", input: "", expectError: true},
+		{name: "not a number", input: "abc", expectError: true},
+		{name: "decimal", input: "2.5", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Int(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Int(%q) should error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Int(%q) = %d, want %d", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInts(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []int
+		expectError bool
+	}{
+		{name: "empty line", input: "", expected: []int{}},
+		{name: "single", input: "5", expected: []int{5}},
+		{name: "mixed signs", input: "1 -2   3\t-4", expected: []int{1, -2, 3, -4}},
+		{name: "invalid field", input: "1 a 3", expectError: true},
+		{name: "decimal field", input: "1 2.5", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Ints(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Ints(%q) should error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Ints(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAppendInts(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []int
+		err      error
+	}{
+		{name: "empty line", input: "", expected: []int{}},
+		{name: "only spaces", input: " \t\r", expected: []int{}},
+		{name: "single", input: "5", expected: []int{5}},
+		{name: "mixed signs", input: "1 -2   3\t-4", expected: []int{1, -2, 3, -4}},
+		{name: "explicit sign and zeros", input: "+007 -0", expected: []int{7, 0}},
+		{name: "largest", input: "9223372036854775807 -9223372036854775808", expected: []int{9223372036854775807, -9223372036854775808}},
+		{name: "too large", input: "9223372036854775808", err: strconv.ErrRange},
+		{name: "too small", input: "-9223372036854775809", err: strconv.ErrRange},
+		{name: "far too large", input: "99999999999999999999999", err: strconv.ErrRange},
+		{name: "invalid field", input: "1 a 3", err: strconv.ErrSyntax},
+		{name: "decimal field", input: "1 2.5", err: strconv.ErrSyntax},
+		{name: "lone sign", input: "1 -", err: strconv.ErrSyntax},
+		{name: "unicode space", input: "1\u00a02", err: strconv.ErrSyntax},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if strconv.IntSize != 64 && strings.Contains(tt.input, "922337") {
+				t.Skip("needs 64-bit ints")
+			}
+			result, err := AppendInts([]int{}, []byte(tt.input))
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("AppendInts(%q) = %v, want %v", tt.input, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("AppendInts(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAppendInts_Appends(t *testing.T) {
+	result, err := AppendInts([]int{1}, []byte("2 3"))
+	if err != nil || !reflect.DeepEqual(result, []int{1, 2, 3}) {
+		t.Errorf("AppendInts() = %v, %v", result, err)
+	}
+	_, err = AppendInts(nil, []byte("1 2 x"))
+	if err == nil || err.Error() != `field 3: strconv.Atoi: parsing "x": invalid syntax` {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestAppendInts_Allocs(t *testing.T) {
+	line := []byte("17 -3   42\t8 1000000")
+	buf := make([]int, 0, 8)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = AppendInts(buf[:0], line)
+	})
+	if allocs != 0 {
+		t.Errorf("AppendInts allocated %.0f times per line", allocs)
+	}
+}
+
+func FuzzAppendInts(f *testing.F) {
+	f.Add("1 -2   3\t-4")
+	f.Add("+5 x")
+	f.Add("9223372036854775808")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, line string) {
+		for _, r := range line {
+			if r >= 0x80 {
+				// Ints also splits on Unicode spaces.
+				return
+			}
+		}
+		expected, expectedErr := Ints(line)
+		result, err := AppendInts(nil, []byte(line))
+		if (err != nil) != (expectedErr != nil) {
+			t.Fatalf("AppendInts(%q) error %v, Ints error %v", line, err, expectedErr)
+		}
+		if err != nil {
+			if err.Error() != expectedErr.Error() {
+				t.Errorf("AppendInts(%q) error %q, Ints error %q", line, err, expectedErr)
+			}
+			return
+		}
+		if len(result) != len(expected) || (len(result) > 0 && !reflect.DeepEqual(result, expected)) {
+			t.Errorf("AppendInts(%q) = %v, Ints = %v", line, result, expected)
+		}
+	})
+}
+
+func benchmarkLine() string {
+	var sb strings.Builder
+	for i := 0; i < 8; i++ {
+		fmt.Fprintf(&sb, "%d   ", 10000+i*7919)
+	}
+	return sb.String()
+}
+
+func BenchmarkInts(b *testing.B) {
+	line := benchmarkLine()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := Ints(line); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAppendInts(b *testing.B) {
+	line := []byte(benchmarkLine())
+	buf := make([]int, 0, 8)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var err error
+		if buf, err = AppendInts(buf[:0], line); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestTuple(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		n           int
+		expected    []int
+		expectError bool
+	}{
+		{name: "pair", input: "3   4", n: 2, expected: []int{3, 4}},
+		{name: "triple", input: "1 -2 3", n: 3, expected: []int{1, -2, 3}},
+		{name: "too few", input: "1", n: 2, expectError: true},
+		{name: "too many", input: "1 2 3", n: 2, expectError: true},
+		{name: "invalid", input: "1 x", n: 2, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Tuple(tt.input, tt.n)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Tuple(%q, %d) should error", tt.input, tt.n)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Tuple(%q, %d) = %v, want %v", tt.input, tt.n, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAllInts(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []int
+	}{
+		{name: "no numbers", input: "hello", expected: []int{}},
+		{name: "embedded", input: "p=10,-3 v=-2,+7", expected: []int{10, -3, -2, 7}},
+		{name: "adjacent text", input: "Button A: X+94, Y+34", expected: []int{94, 34}},
+		{name: "hyphen between numbers", input: "3-4", expected: []int{3, -4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := AllInts(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("AllInts(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGrid(t *testing.T) {
+	grid, err := Grid([]string{"ab", "cd", ""})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]byte{[]byte("ab"), []byte("cd")}
+	if !reflect.DeepEqual(grid, expected) {
+		t.Errorf("Grid() = %q, want %q", grid, expected)
+	}
+
+	if _, err := Grid([]string{"abc", "de"}); err == nil {
+		t.Error("Grid() should error on ragged rows")
+	}
+
+	grid, err = Grid(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(grid) != 0 {
+		t.Errorf("Grid(nil) = %q, want empty", grid)
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected [][]string
+	}{
+		{
+			name:     "empty",
+			input:    "",
+			expected: [][]string{},
+		},
+		{
+			name:     "single block",
+			input:    "a\nb\n",
+			expected: [][]string{{"a", "b"}},
+		},
+		{
+			name:     "several blocks",
+			input:    "a\nb\n\n\nc\r\n\r\nd",
+			expected: [][]string{{"a", "b"}, {"c"}, {"d"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Blocks(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Blocks(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
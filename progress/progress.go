@@ -0,0 +1,178 @@
+// Package progress reports how far a long solve has got.
+//
+// Reporting is optional: solvers look the Reporter up with FromContext and
+// skip all bookkeeping when it is nil.
+package progress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Reporter interface {
+	AddBytes(n int64)
+	AddRows(n int)
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, r Reporter) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+func FromContext(ctx context.Context) Reporter {
+	r, _ := ctx.Value(contextKey{}).(Reporter)
+	return r
+}
+
+type countingReader struct {
+	r   io.Reader
+	rep Reporter
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.rep.AddBytes(int64(n))
+	return n, err
+}
+
+// Reader reports every byte read through r to rep. It returns r itself
+// when rep is nil.
+func Reader(r io.Reader, rep Reporter) io.Reader {
+	if rep == nil {
+		return r
+	}
+	return &countingReader{r: r, rep: rep}
+}
+
+// IsTerminal reports whether w is a character device, i.e. worth drawing
+// a bar on rather than printing log lines.
+func IsTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+const barWidth = 30
+
+// Bar is a Reporter that periodically renders its counters to a writer,
+// either as a bar redrawn in place on a terminal or as plain log lines.
+type Bar struct {
+	w        io.Writer
+	label    string
+	total    atomic.Int64
+	tty      bool
+	start    time.Time
+	bytes    atomic.Int64
+	rows     atomic.Int64
+	stop     chan struct{}
+	finished sync.WaitGroup
+}
+
+// Start begins rendering every interval until Stop is called.
+func Start(w io.Writer, label string, tty bool, interval time.Duration) *Bar {
+	b := &Bar{
+		w:     w,
+		label: label,
+		tty:   tty,
+		start: time.Now(),
+		stop:  make(chan struct{}),
+	}
+
+	b.finished.Add(1)
+	go func() {
+		defer b.finished.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				b.render()
+			case <-b.stop:
+				return
+			}
+		}
+	}()
+	return b
+}
+
+// SetTotal sets the expected number of bytes; 0 means unknown.
+func (b *Bar) SetTotal(n int64) {
+	b.total.Store(n)
+}
+
+func (b *Bar) AddBytes(n int64) {
+	b.bytes.Add(n)
+}
+
+func (b *Bar) AddRows(n int) {
+	b.rows.Add(int64(n))
+}
+
+// Stop renders the final state and stops the background rendering.
+func (b *Bar) Stop() {
+	close(b.stop)
+	b.finished.Wait()
+	b.render()
+	if b.tty {
+		fmt.Fprintln(b.w)
+	}
+}
+
+func (b *Bar) render() {
+	line := b.Line(time.Since(b.start))
+	if b.tty {
+		fmt.Fprintf(b.w, "\r\033[K%s", line)
+	} else {
+		fmt.Fprintln(b.w, line)
+	}
+}
+
+// Line describes the current state as if elapsed time had passed.
+func (b *Bar) Line(elapsed time.Duration) string {
+	bytes := b.bytes.Load()
+	rows := b.rows.Load()
+	total := b.total.Load()
+
+	var sb strings.Builder
+	sb.WriteString(b.label)
+	sb.WriteString(": ")
+	if total > 0 {
+		fraction := min(float64(bytes)/float64(total), 1)
+		if b.tty {
+			filled := int(fraction * barWidth)
+			sb.WriteString("[" + strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled) + "] ")
+		}
+		fmt.Fprintf(&sb, "%3.0f%% %s/%s", fraction*100, FormatBytes(bytes), FormatBytes(total))
+	} else {
+		sb.WriteString(FormatBytes(bytes))
+	}
+	fmt.Fprintf(&sb, ", %d rows, %s elapsed", rows, elapsed.Round(time.Second))
+	if total > 0 && bytes > 0 && bytes < total {
+		eta := time.Duration(float64(elapsed) * float64(total-bytes) / float64(bytes))
+		fmt.Fprintf(&sb, ", ETA %s", eta.Round(time.Second))
+	}
+	return sb.String()
+}
+
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
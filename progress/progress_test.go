@@ -0,0 +1,122 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int64
+		bytes    int64
+		rows     int
+		tty      bool
+		expected string
+	}{
+		{
+			name:     "unknown total",
+			bytes:    2048,
+			rows:     10,
+			expected: "Part1: 2.0 KiB, 10 rows, 4s elapsed",
+		},
+		{
+			name:     "half way",
+			total:    4096,
+			bytes:    2048,
+			rows:     10,
+			expected: "Part1:  50% 2.0 KiB/4.0 KiB, 10 rows, 4s elapsed, ETA 4s",
+		},
+		{
+			name:     "done",
+			total:    4096,
+			bytes:    4096,
+			expected: "Part1: 100% 4.0 KiB/4.0 KiB, 0 rows, 4s elapsed",
+		},
+		{
+			name:     "terminal bar",
+			total:    100,
+			bytes:    50,
+			tty:      true,
+			expected: "Part1: [===============               ]  50% 50 B/100 B, 0 rows, 4s elapsed, ETA 4s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Bar{label: "Part1", tty: tt.tty}
+			b.SetTotal(tt.total)
+			b.AddBytes(tt.bytes)
+			b.AddRows(tt.rows)
+			if line := b.Line(4 * time.Second); line != tt.expected {
+				t.Errorf("Line() = %q, want %q", line, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n        int64
+		expected string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 << 20, "5.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		if result := FormatBytes(tt.n); result != tt.expected {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.n, result, tt.expected)
+		}
+	}
+}
+
+func TestReader(t *testing.T) {
+	src := strings.NewReader("hello")
+	if r := Reader(src, nil); r != io.Reader(src) {
+		t.Error("Reader with a nil reporter should return its input")
+	}
+
+	b := &Bar{}
+	content, err := io.ReadAll(Reader(strings.NewReader("hello"), b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "hello" || b.bytes.Load() != 5 {
+		t.Errorf("read %q and counted %d bytes", content, b.bytes.Load())
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if rep := FromContext(context.Background()); rep != nil {
+		t.Errorf("FromContext() = %v, want nil", rep)
+	}
+	b := &Bar{}
+	if rep := FromContext(NewContext(context.Background(), b)); rep != b {
+		t.Errorf("FromContext() = %v, want %v", rep, b)
+	}
+}
+
+func TestStart(t *testing.T) {
+	var out bytes.Buffer
+	b := Start(&out, "Part2", false, time.Hour)
+	b.SetTotal(10)
+	b.AddBytes(10)
+	b.AddRows(3)
+	b.Stop()
+
+	if expected := "Part2: 100% 10 B/10 B, 3 rows, 0s elapsed\n"; out.String() != expected {
+		t.Errorf("output = %q, want %q", out.String(), expected)
+	}
+	if IsTerminal(&out) {
+		t.Error("a buffer is not a terminal")
+	}
+}
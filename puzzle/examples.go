@@ -0,0 +1,66 @@
+package puzzle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Case is an example input together with the answers the description gives
+// for it, by part. Name is "exampleN", numbered like Examples.
+type Case struct {
+	Name    string
+	Input   string
+	Answers map[int]int
+}
+
+// Cases pairs each part's example with its expected answer. The input is
+// the first block of the part, and the answer the last emphasised number in
+// code, which is how the puzzles end their walk through the example. A
+// part without a block of its own reuses the previous part's example.
+func (p *Puzzle) Cases() []Case {
+	var cases []Case
+	blocks := 0
+	for i, article := range p.articles {
+		part := i + 1
+		pres := article.find("pre")
+		first := blocks
+		blocks += len(pres)
+
+		answer, ok := lastAnswer(article)
+		switch {
+		case !ok:
+		case len(pres) > 0:
+			cases = append(cases, Case{
+				Name:    fmt.Sprintf("example%d", first+1),
+				Input:   pres[0].textContent(),
+				Answers: map[int]int{part: answer},
+			})
+		case len(cases) > 0:
+			cases[len(cases)-1].Answers[part] = answer
+		}
+	}
+	return cases
+}
+
+// lastAnswer finds the last <code><em>N</em></code>, or <em><code>N</code></em>,
+// in n.
+func lastAnswer(n *node) (int, bool) {
+	answer, found := 0, false
+	var walk func(n *node, inCode, inEm bool)
+	walk = func(n *node, inCode, inEm bool) {
+		inCode = inCode || n.tag == "code"
+		inEm = inEm || n.tag == "em"
+		if (n.tag == "code" && inEm) || (n.tag == "em" && inCode) {
+			if value, err := strconv.Atoi(strings.TrimSpace(n.textContent())); err == nil {
+				answer, found = value, true
+			}
+			return
+		}
+		for _, child := range n.children {
+			walk(child, inCode, inEm)
+		}
+	}
+	walk(n, false, false)
+	return answer, found
+}
@@ -0,0 +1,97 @@
+package puzzle
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var spacePattern = regexp.MustCompile(`\s+`)
+
+// Markdown renders the description. Links relative to the site are made
+// absolute against pageURL, the address the page was downloaded from.
+func (p *Puzzle) Markdown(pageURL string) string {
+	base, _ := url.Parse(pageURL)
+	r := renderer{base: base}
+	for _, article := range p.articles {
+		r.block(article)
+	}
+	return strings.TrimRight(r.sb.String(), "\n") + "\n"
+}
+
+type renderer struct {
+	sb   strings.Builder
+	base *url.URL
+}
+
+func (r *renderer) block(n *node) {
+	switch n.tag {
+	case "":
+		if text := strings.TrimSpace(n.text); text != "" {
+			r.sb.WriteString(text + "\n\n")
+		}
+	case "h2":
+		r.sb.WriteString("## " + strings.Trim(n.textContent(), "- ") + "\n\n")
+	case "p":
+		r.sb.WriteString(strings.TrimSpace(r.inline(n)) + "\n\n")
+	case "pre":
+		text := n.textContent()
+		if !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		r.sb.WriteString("```\n" + text + "```\n\n")
+	case "ul", "ol":
+		for i, li := range n.find("li") {
+			marker := "- "
+			if n.tag == "ol" {
+				marker = strconv.Itoa(i+1) + ". "
+			}
+			r.sb.WriteString(marker + strings.TrimSpace(r.inline(li)) + "\n")
+		}
+		r.sb.WriteString("\n")
+	case "article", "div", "section":
+		for _, child := range n.children {
+			r.block(child)
+		}
+	default:
+		r.sb.WriteString(strings.TrimSpace(r.inline(n)) + "\n\n")
+	}
+}
+
+func (r *renderer) inline(n *node) string {
+	switch n.tag {
+	case "":
+		return spacePattern.ReplaceAllString(n.text, " ")
+	case "code":
+		if len(n.find("em")) > 0 {
+			return "**`" + n.textContent() + "`**"
+		}
+		return "`" + n.textContent() + "`"
+	case "em":
+		if len(n.find("code")) > 0 {
+			return "**`" + n.textContent() + "`**"
+		}
+		return "*" + r.children(n) + "*"
+	case "a":
+		return "[" + r.children(n) + "](" + r.resolve(n.attrs["href"]) + ")"
+	default:
+		return r.children(n)
+	}
+}
+
+func (r *renderer) children(n *node) string {
+	var sb strings.Builder
+	for _, child := range n.children {
+		sb.WriteString(r.inline(child))
+	}
+	return sb.String()
+}
+
+func (r *renderer) resolve(href string) string {
+	ref, err := url.Parse(href)
+	if err != nil || r.base == nil {
+		return href
+	}
+	return r.base.ResolveReference(ref).String()
+}
@@ -0,0 +1,120 @@
+// Package puzzle reads the description out of a day's page on the site:
+// its text as Markdown, and the example inputs shown in it.
+package puzzle
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// node is an element of an article, or a piece of text when tag is empty.
+type node struct {
+	tag      string
+	attrs    map[string]string
+	text     string
+	children []*node
+}
+
+type Puzzle struct {
+	articles []*node
+}
+
+var (
+	articlePattern = regexp.MustCompile(`(?s)<article class="day-desc">.*?</article>`)
+
+	ErrNoArticle = errors.New("no puzzle description on the page")
+)
+
+// Parse finds the puzzle descriptions, one per part, in a day's page. Only
+// the articles are parsed, so the rest of the page, scripts included, never
+// reaches the XML decoder.
+func Parse(page []byte) (*Puzzle, error) {
+	var p Puzzle
+	for _, article := range articlePattern.FindAll(page, -1) {
+		root, err := parseHTML(article)
+		if err != nil {
+			return nil, err
+		}
+		p.articles = append(p.articles, root.children...)
+	}
+	if len(p.articles) == 0 {
+		return nil, ErrNoArticle
+	}
+	return &p, nil
+}
+
+func parseHTML(fragment []byte) (*node, error) {
+	d := xml.NewDecoder(bytes.NewReader(fragment))
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+
+	root := &node{}
+	stack := []*node{root}
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return root, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		top := stack[len(stack)-1]
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			n := &node{tag: strings.ToLower(tok.Name.Local), attrs: map[string]string{}}
+			for _, attr := range tok.Attr {
+				n.attrs[attr.Name.Local] = attr.Value
+			}
+			top.children = append(top.children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			top.children = append(top.children, &node{text: string(tok)})
+		}
+	}
+}
+
+// textContent is the text of n and everything in it, without markup.
+func (n *node) textContent() string {
+	if n.tag == "" {
+		return n.text
+	}
+	var sb strings.Builder
+	for _, child := range n.children {
+		sb.WriteString(child.textContent())
+	}
+	return sb.String()
+}
+
+// find returns the elements with the given tag in n, in document order.
+func (n *node) find(tag string) []*node {
+	var found []*node
+	for _, child := range n.children {
+		if child.tag == tag {
+			found = append(found, child)
+		}
+		found = append(found, child.find(tag)...)
+	}
+	return found
+}
+
+// Examples returns the contents of the preformatted blocks, which is where
+// the puzzles show their example inputs.
+func (p *Puzzle) Examples() []string {
+	var examples []string
+	for _, article := range p.articles {
+		for _, pre := range article.find("pre") {
+			examples = append(examples, pre.textContent())
+		}
+	}
+	return examples
+}
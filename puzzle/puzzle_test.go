@@ -0,0 +1,98 @@
+package puzzle
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func readPuzzle(t *testing.T) *Puzzle {
+	t.Helper()
+	page, err := os.ReadFile("testdata/day.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Parse(page)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return p
+}
+
+func TestMarkdown(t *testing.T) {
+	expected, err := os.ReadFile("testdata/day.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md := readPuzzle(t).Markdown("https://adventofcode.com/2024/day/1"); md != string(expected) {
+		t.Errorf("Expected Markdown:\n%s\nGot:\n%s", expected, md)
+	}
+}
+
+func TestExamples(t *testing.T) {
+	expected := []string{"3   4\n4   3\n2   5\n", "3   4\n4   3\n"}
+	if examples := readPuzzle(t).Examples(); !reflect.DeepEqual(examples, expected) {
+		t.Errorf("Examples() = %q, want %q", examples, expected)
+	}
+}
+
+func TestParse_NoArticle(t *testing.T) {
+	if _, err := Parse([]byte("<html><body><p>404 Not Found</p></body></html>")); !errors.Is(err, ErrNoArticle) {
+		t.Errorf("expected ErrNoArticle, got %v", err)
+	}
+}
+
+func TestCases(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     string
+		expected []Case
+	}{
+		{
+			name: "shared example",
+			page: `<article class="day-desc"><h2>--- Day 1 ---</h2><pre><code>1 2
+</code></pre><p>Not this <code><em>x</em></code> or <code>3</code> but <code><em>7</em></code>.</p></article>
+<article class="day-desc"><h2>--- Part Two ---</h2><p>Now it is <em><code>9</code></em>.</p></article>`,
+			expected: []Case{{Name: "example1", Input: "1 2\n", Answers: map[int]int{1: 7, 2: 9}}},
+		},
+		{
+			name: "example per part",
+			page: `<article class="day-desc"><pre><code>a
+</code></pre><pre><code>state
+</code></pre><p>So <code><em>1</em></code>.</p></article>
+<article class="day-desc"><pre><code>b
+</code></pre><p>So <code><em>2</em></code>.</p></article>`,
+			expected: []Case{
+				{Name: "example1", Input: "a\n", Answers: map[int]int{1: 1}},
+				{Name: "example3", Input: "b\n", Answers: map[int]int{2: 2}},
+			},
+		},
+		{
+			name:     "no answer",
+			page:     `<article class="day-desc"><pre><code>a</code></pre><p><em>What is it?</em></p></article>`,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Parse([]byte(tt.page))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cases := p.Cases(); !reflect.DeepEqual(cases, tt.expected) {
+				t.Errorf("Cases() = %+v, want %+v", cases, tt.expected)
+			}
+		})
+	}
+
+	cases := readPuzzle(t).Cases()
+	expected := []Case{
+		{Name: "example1", Input: "3   4\n4   3\n2   5\n", Answers: map[int]int{1: 11}},
+		{Name: "example2", Input: "3   4\n4   3\n", Answers: map[int]int{2: 31}},
+	}
+	if !reflect.DeepEqual(cases, expected) {
+		t.Errorf("Cases() = %+v, want %+v", cases, expected)
+	}
+}
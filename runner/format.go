@@ -0,0 +1,102 @@
+package runner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Result is what a run reports for each part. Duration is encoded in
+// nanoseconds and InputHash is the hex SHA-256 of the input file as
+// stored, before decompression. Warning is set when the answer disagrees
+// with the submission history.
+type Result struct {
+	Day       int           `json:"day"`
+	Part      int           `json:"part"`
+	Answer    int           `json:"answer"`
+	Duration  time.Duration `json:"duration"`
+	InputHash string        `json:"input_hash"`
+	Error     string        `json:"error,omitempty"`
+	Warning   string        `json:"warning,omitempty"`
+}
+
+type formatter interface {
+	Write(res Result) error
+}
+
+var formats = map[string]func(w io.Writer) formatter{
+	"text": func(w io.Writer) formatter { return textFormatter{w} },
+	"json": func(w io.Writer) formatter { return jsonFormatter{json.NewEncoder(w)} },
+	"csv":  newCSVFormatter,
+}
+
+func newFormatter(name string, w io.Writer) (formatter, error) {
+	newFormatter, ok := formats[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q (want text, json or csv)", name)
+	}
+	return newFormatter(w), nil
+}
+
+// textFormatter keeps the historical output. Errors are left to the
+// caller, which prints them on stderr.
+type textFormatter struct {
+	w io.Writer
+}
+
+func (f textFormatter) Write(res Result) error {
+	if res.Error != "" {
+		return nil
+	}
+	_, err := io.WriteString(f.w, TextResult(res.Part, res.Answer))
+	return err
+}
+
+// TextResult is the line text output prints for one part, which is also
+// what the golden .expected files in testdata hold.
+func TextResult(part, answer int) string {
+	return fmt.Sprintf("Part%d result:  %d\n", part, answer)
+}
+
+// jsonFormatter writes one JSON object per line.
+type jsonFormatter struct {
+	enc *json.Encoder
+}
+
+func (f jsonFormatter) Write(res Result) error {
+	return f.enc.Encode(res)
+}
+
+type csvFormatter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func newCSVFormatter(w io.Writer) formatter {
+	return &csvFormatter{w: csv.NewWriter(w)}
+}
+
+func (f *csvFormatter) Write(res Result) error {
+	if !f.header {
+		f.w.Write([]string{"day", "part", "answer", "duration", "input_hash", "error", "warning"})
+		f.header = true
+	}
+	answer := strconv.Itoa(res.Answer)
+	if res.Error != "" {
+		answer = ""
+	}
+	f.w.Write([]string{
+		strconv.Itoa(res.Day),
+		strconv.Itoa(res.Part),
+		answer,
+		strconv.FormatInt(int64(res.Duration), 10),
+		res.InputHash,
+		res.Error,
+		res.Warning,
+	})
+	f.w.Flush()
+	return f.w.Error()
+}
@@ -0,0 +1,84 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strings"
+)
+
+// profiles holds the -cpuprofile, -memprofile and -trace file names. Each
+// part gets its own files, named after these with the day and part added
+// before the extension: cpu.prof becomes cpu.day02.part1.prof.
+type profiles struct {
+	cpu   string
+	mem   string
+	trace string
+}
+
+func profileName(name string, day, part int) string {
+	ext := filepath.Ext(name)
+	return fmt.Sprintf("%s.day%02d.part%d%s", strings.TrimSuffix(name, ext), day, part, ext)
+}
+
+// start begins profiling one part. The returned function stops it and
+// writes the memory profile, which is taken when the part is done.
+func (p profiles) start(day, part int) (func() error, error) {
+	var stops []func() error
+	stop := func() error {
+		var errs []error
+		for i := len(stops) - 1; i >= 0; i-- {
+			errs = append(errs, stops[i]())
+		}
+		return errors.Join(errs...)
+	}
+
+	if p.cpu != "" {
+		f, err := os.Create(profileName(p.cpu, day, part))
+		if err != nil {
+			return nil, err
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+		stops = append(stops, func() error {
+			pprof.StopCPUProfile()
+			return f.Close()
+		})
+	}
+
+	if p.trace != "" {
+		f, err := os.Create(profileName(p.trace, day, part))
+		if err != nil {
+			return nil, errors.Join(err, stop())
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			return nil, errors.Join(err, stop())
+		}
+		stops = append(stops, func() error {
+			trace.Stop()
+			return f.Close()
+		})
+	}
+
+	if p.mem != "" {
+		name := profileName(p.mem, day, part)
+		stops = append(stops, func() error {
+			f, err := os.Create(name)
+			if err != nil {
+				return err
+			}
+			// Like go test -memprofile: collect first so the profile
+			// shows what is still live, next to the totals allocated.
+			runtime.GC()
+			return errors.Join(pprof.WriteHeapProfile(f), f.Close())
+		})
+	}
+	return stop, nil
+}
@@ -0,0 +1,308 @@
+// Package runner implements the command line shared by every day.
+package runner
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"adventofcode2024/history"
+	"adventofcode2024/input"
+	"adventofcode2024/progress"
+)
+
+// ErrNotImplemented is returned by the stubs of a freshly generated day.
+// The runner reports it and moves on to the next part instead of failing.
+var ErrNotImplemented = errors.New("not implemented")
+
+type Solver func(ctx context.Context, r io.Reader) (int, error)
+
+type Day struct {
+	Number int
+	Part1  Solver
+	Part2  Solver
+}
+
+// Env is everything a run touches outside the process, so tests can swap
+// it for in-memory versions. Fetch, if set, is asked for the puzzle input
+// when the default input file does not exist. History, if set, is used to
+// warn about answers that the site has already rejected or that differ from
+// the accepted one.
+type Env struct {
+	Stdin   io.Reader
+	Stdout  io.Writer
+	Stderr  io.Writer
+	FS      fs.FS
+	Fetch   func(ctx context.Context, day int) ([]byte, error)
+	History *history.History
+}
+
+const defaultInput = "input"
+
+// OSFS opens names with os.Open, so unlike os.DirFS it accepts absolute
+// paths and paths that climb out of the working directory, which is what
+// users expect from -input.
+type OSFS struct{}
+
+func (OSFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+type options struct {
+	input    string
+	part     int
+	timeout  time.Duration
+	progress bool
+	format   string
+	profiles profiles
+}
+
+func Run(ctx context.Context, day Day, args []string, env Env) error {
+	var opts options
+	flags := flag.NewFlagSet(fmt.Sprintf("day%02d", day.Number), flag.ContinueOnError)
+	flags.SetOutput(env.Stderr)
+	flags.StringVar(&opts.input, "input", defaultInput, `puzzle input file, or "-" for stdin`)
+	flags.IntVar(&opts.part, "part", 0, "solve only this part (1 or 2)")
+	flags.DurationVar(&opts.timeout, "timeout", 0, "give up on a part after this long (0 means no limit)")
+	flags.BoolVar(&opts.progress, "progress", false, "report progress on stderr")
+	flags.StringVar(&opts.format, "format", "text", "output format: text, json or csv")
+	flags.StringVar(&opts.profiles.cpu, "cpuprofile", "", "write a CPU profile for each part, named after this file")
+	flags.StringVar(&opts.profiles.mem, "memprofile", "", "write a memory profile for each part, named after this file")
+	flags.StringVar(&opts.profiles.trace, "trace", "", "write an execution trace for each part, named after this file")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", flags.Args())
+	}
+	if opts.part < 0 || opts.part > 2 {
+		return fmt.Errorf("invalid part %d", opts.part)
+	}
+	out, err := newFormatter(opts.format, env.Stdout)
+	if err != nil {
+		return err
+	}
+	// The hash costs a pass over the whole input, so it is skipped unless
+	// it is printed or needed to look answers up in the history.
+	hash := opts.format != "text" || env.History != nil
+
+	open, err := opener(ctx, day, opts.input, env)
+	if err != nil {
+		return err
+	}
+
+	parts := []Solver{day.Part1, day.Part2}
+	for i, solve := range parts {
+		if opts.part != 0 && opts.part != i+1 {
+			continue
+		}
+		var bar *progress.Bar
+		if opts.progress {
+			bar = startBar(env.Stderr, fmt.Sprintf("Part%d", i+1))
+		}
+		stopProfiles, err := opts.profiles.start(day.Number, i+1)
+		if err != nil {
+			return err
+		}
+		res := Result{Day: day.Number, Part: i + 1}
+		start := time.Now()
+		res.Answer, res.InputHash, err = solvePart(ctx, solve, open, opts.timeout, bar, hash)
+		res.Duration = time.Since(start)
+		if stopErr := stopProfiles(); stopErr != nil {
+			return stopErr
+		}
+		if errors.Is(err, context.DeadlineExceeded) && opts.timeout > 0 {
+			err = fmt.Errorf("part %d timed out after %v: %w", i+1, opts.timeout, err)
+		}
+		if err != nil {
+			res.Error = err.Error()
+		} else if env.History != nil {
+			if err := env.History.Verify(day.Number, res.Part, res.InputHash, res.Answer); err != nil {
+				res.Warning = err.Error()
+				fmt.Fprintf(env.Stderr, "warning: %v\n", err)
+			}
+		}
+		if err := out.Write(res); err != nil {
+			return err
+		}
+		if errors.Is(err, ErrNotImplemented) {
+			fmt.Fprintf(env.Stderr, "Part%d: not implemented\n", i+1)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type source struct {
+	io.Reader
+	io.Closer
+	size int64
+}
+
+// opener returns a function that opens a fresh, still compressed, reader
+// over the input for every part. Stdin can only be read once, so it is
+// buffered up front, and so is a fetched input. Files on disk are mapped
+// into memory where possible, like input.Open does.
+func opener(ctx context.Context, day Day, name string, env Env) (func() (*source, error), error) {
+	fetch := false
+	if name == defaultInput && env.Fetch != nil {
+		_, err := fs.Stat(env.FS, name)
+		fetch = errors.Is(err, fs.ErrNotExist)
+	}
+
+	var content []byte
+	var err error
+	switch {
+	case name == "-":
+		if content, err = io.ReadAll(env.Stdin); err != nil {
+			return nil, err
+		}
+	case fetch:
+		fmt.Fprintf(env.Stderr, "%s not found, fetching the input for day %d\n", name, day.Number)
+		if content, err = env.Fetch(ctx, day.Number); err != nil {
+			return nil, fmt.Errorf("fetching input: %w", err)
+		}
+	default:
+		return func() (*source, error) {
+			file, err := env.FS.Open(name)
+			if err != nil {
+				return nil, err
+			}
+			if f, ok := file.(*os.File); ok {
+				if m, err := input.Map(f); err == nil {
+					f.Close()
+					return &source{Reader: m, Closer: m, size: int64(len(m.Bytes()))}, nil
+				}
+			}
+			var size int64
+			if info, err := file.Stat(); err == nil {
+				size = info.Size()
+			}
+			return &source{Reader: file, Closer: file, size: size}, nil
+		}, nil
+	}
+
+	return func() (*source, error) {
+		return &source{Reader: bytes.NewReader(content), Closer: io.NopCloser(nil), size: int64(len(content))}, nil
+	}, nil
+}
+
+func startBar(w io.Writer, label string) *progress.Bar {
+	tty := progress.IsTerminal(w)
+	interval := 2 * time.Second
+	if tty {
+		interval = 100 * time.Millisecond
+	}
+	return progress.Start(w, label, tty, interval)
+}
+
+// solvePart runs one part on a fresh reader. Progress and the hash, when
+// enabled, are computed on the raw bytes so that they match the file even
+// for compressed inputs. A mapped input is hashed in one go and handed to
+// the solver as it is, so that it can be read in place.
+func solvePart(ctx context.Context, solve Solver, open func() (*source, error), timeout time.Duration, bar *progress.Bar, hash bool) (int, string, error) {
+	if bar != nil {
+		defer bar.Stop()
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	src, err := open()
+	if err != nil {
+		return 0, "", err
+	}
+	defer src.Close()
+
+	var r io.Reader = src
+	var sum string
+	if bar != nil {
+		bar.SetTotal(src.size)
+		ctx = progress.NewContext(ctx, bar)
+	}
+	h := sha256.New()
+	if b, ok := src.Reader.(input.Buffered); ok {
+		if hash {
+			h.Write(b.Bytes())
+			sum = hex.EncodeToString(h.Sum(nil))
+		}
+		r = b
+		if bar != nil {
+			r = progressBuffer{Buffered: b, bar: bar}
+		}
+	} else {
+		if bar != nil {
+			r = progress.Reader(r, bar)
+		}
+		if hash {
+			r = io.TeeReader(r, h)
+		}
+	}
+
+	rc, err := input.NewReader(r)
+	if err != nil {
+		return 0, "", err
+	}
+	defer rc.Close()
+
+	result, err := solve(ctx, rc)
+	if err != nil || !hash {
+		return result, "", err
+	}
+	if sum != "" {
+		return result, sum, nil
+	}
+	// Solvers may stop before the end of the input, so read whatever is
+	// left to hash the whole file.
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return 0, "", err
+	}
+	return result, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// progressBuffer reports the bytes taken from a Buffered input, whether
+// they are read or consumed in place.
+type progressBuffer struct {
+	input.Buffered
+	bar *progress.Bar
+}
+
+func (p progressBuffer) Read(b []byte) (int, error) {
+	n, err := p.Buffered.Read(b)
+	p.bar.AddBytes(int64(n))
+	return n, err
+}
+
+func (p progressBuffer) Next(n int) []byte {
+	b := p.Buffered.Next(n)
+	p.bar.AddBytes(int64(len(b)))
+	return b
+}
+
+const checkInterval = 1024
+
+// Checkpoint is meant for the hot loops of solvers: it returns ctx.Err()
+// on every 1024th iteration and nil otherwise, which keeps the cost of
+// checking for cancellation out of the profile.
+func Checkpoint(ctx context.Context, iteration int) error {
+	if iteration%checkInterval != 0 {
+		return nil
+	}
+	return ctx.Err()
+}
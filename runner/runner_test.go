@@ -0,0 +1,547 @@
+package runner
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"adventofcode2024/history"
+	"adventofcode2024/input"
+	"adventofcode2024/progress"
+)
+
+func countLines(_ context.Context, r io.Reader) (int, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	return strings.Count(string(content), "\n"), nil
+}
+
+func countBytes(_ context.Context, r io.Reader) (int, error) {
+	content, err := io.ReadAll(r)
+	return len(content), err
+}
+
+var testDay = Day{Number: 7, Part1: countLines, Part2: countBytes}
+
+func runDay(t *testing.T, day Day, args []string, stdin string, fsys fstest.MapFS) (string, string, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	err := Run(context.Background(), day, args, Env{
+		Stdin:  strings.NewReader(stdin),
+		Stdout: &stdout,
+		Stderr: &stderr,
+		FS:     fsys,
+	})
+	return stdout.String(), stderr.String(), err
+}
+
+func TestRun(t *testing.T) {
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write([]byte("a\nb\n"))
+	zw.Close()
+
+	fsys := fstest.MapFS{
+		"input":    {Data: []byte("a\nb\n")},
+		"input.gz": {Data: compressed.Bytes()},
+	}
+
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+	}{
+		{name: "default input", args: nil},
+		{name: "named input", args: []string{"-input", "input"}},
+		{name: "compressed input", args: []string{"-input", "input.gz"}},
+		{name: "stdin", args: []string{"-input", "-"}, stdin: "a\nb\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, _, err := runDay(t, testDay, tt.args, tt.stdin, fsys)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := "Part1 result:  2\nPart2 result:  4\n"
+			if stdout != expected {
+				t.Errorf("Expected output:\n%s\nGot:\n%s", expected, stdout)
+			}
+		})
+	}
+}
+
+func TestRun_OSFS(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "input")
+	if err := os.WriteFile(name, []byte("a\nb\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	err := Run(context.Background(), testDay, []string{"-input", name}, Env{
+		Stdin:  strings.NewReader(""),
+		Stdout: &stdout,
+		Stderr: io.Discard,
+		FS:     OSFS{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "Part1 result:  2\nPart2 result:  4\n"; stdout.String() != expected {
+		t.Errorf("Expected output:\n%s\nGot:\n%s", expected, stdout.String())
+	}
+}
+
+func TestRun_Mapped(t *testing.T) {
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write([]byte("a\nb\n"))
+	zw.Close()
+
+	tests := []struct {
+		name     string
+		content  []byte
+		buffered bool
+	}{
+		{name: "plain", content: []byte("a\nb\n"), buffered: runtime.GOOS == "linux"},
+		{name: "gzip", content: compressed.Bytes()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "input")
+			if err := os.WriteFile(name, tt.content, 0644); err != nil {
+				t.Fatal(err)
+			}
+			buffered := false
+			day := Day{Number: 7, Part1: func(ctx context.Context, r io.Reader) (int, error) {
+				_, buffered = r.(input.Buffered)
+				lines := 0
+				for _, err := range input.ByteLines(r) {
+					if err != nil {
+						return 0, err
+					}
+					lines++
+				}
+				return lines, nil
+			}}
+
+			var stdout, stderr bytes.Buffer
+			err := Run(context.Background(), day, []string{"-input", name, "-part", "1", "-format", "json", "-progress"}, Env{
+				Stdin:  strings.NewReader(""),
+				Stdout: &stdout,
+				Stderr: &stderr,
+				FS:     OSFS{},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buffered != tt.buffered {
+				t.Errorf("solver got a Buffered reader: %v, want %v", buffered, tt.buffered)
+			}
+
+			var res Result
+			if err := json.Unmarshal(stdout.Bytes(), &res); err != nil {
+				t.Fatalf("decoding %q: %v", stdout.String(), err)
+			}
+			sum := sha256.Sum256(tt.content)
+			if res.Answer != 2 || res.InputHash != hex.EncodeToString(sum[:]) {
+				t.Errorf("unexpected result %+v", res)
+			}
+			size := progress.FormatBytes(int64(len(tt.content)))
+			if want := fmt.Sprintf("Part1: 100%% %s/%s", size, size); !strings.Contains(stderr.String(), want) {
+				t.Errorf("stderr %q does not contain %q", stderr.String(), want)
+			}
+		})
+	}
+}
+
+func TestRun_Fetch(t *testing.T) {
+	fetched := 0
+	env := func(stdout io.Writer, fsys fstest.MapFS) Env {
+		return Env{
+			Stdin:  strings.NewReader(""),
+			Stdout: stdout,
+			Stderr: io.Discard,
+			FS:     fsys,
+			Fetch: func(_ context.Context, day int) ([]byte, error) {
+				fetched++
+				if day != 7 {
+					t.Errorf("fetched day %d", day)
+				}
+				return []byte("a\nb\n"), nil
+			},
+		}
+	}
+
+	var stdout bytes.Buffer
+	if err := Run(context.Background(), testDay, nil, env(&stdout, fstest.MapFS{})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "Part1 result:  2\nPart2 result:  4\n"; stdout.String() != expected {
+		t.Errorf("Expected output:\n%s\nGot:\n%s", expected, stdout.String())
+	}
+	if fetched != 1 {
+		t.Errorf("expected one fetch, got %d", fetched)
+	}
+
+	// An existing input, or one named explicitly, is never fetched.
+	fsys := fstest.MapFS{"input": {Data: []byte("x\n")}}
+	if err := Run(context.Background(), testDay, nil, env(io.Discard, fsys)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := Run(context.Background(), testDay, []string{"-input", "other"}, env(io.Discard, fsys)); err == nil {
+		t.Error("expected an error for a missing named input")
+	}
+	if fetched != 1 {
+		t.Errorf("expected no more fetches, got %d", fetched)
+	}
+}
+
+func TestRun_Part(t *testing.T) {
+	fsys := fstest.MapFS{"input": {Data: []byte("a\nb\n")}}
+	tests := []struct {
+		part     string
+		expected string
+	}{
+		{part: "0", expected: "Part1 result:  2\nPart2 result:  4\n"},
+		{part: "1", expected: "Part1 result:  2\n"},
+		{part: "2", expected: "Part2 result:  4\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.part, func(t *testing.T) {
+			stdout, _, err := runDay(t, testDay, []string{"-part", tt.part}, "", fsys)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stdout != tt.expected {
+				t.Errorf("Expected output:\n%s\nGot:\n%s", tt.expected, stdout)
+			}
+		})
+	}
+}
+
+func TestRun_Help(t *testing.T) {
+	stdout, stderr, err := runDay(t, testDay, []string{"-h"}, "", fstest.MapFS{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "" {
+		t.Errorf("expected no output, got %q", stdout)
+	}
+	if !strings.Contains(stderr, "Usage of day07") || !strings.Contains(stderr, "-input") {
+		t.Errorf("expected usage on stderr, got %q", stderr)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	failure := errors.New("part 2 failed")
+	failing := Day{Number: 7, Part1: countLines, Part2: func(context.Context, io.Reader) (int, error) {
+		return 0, failure
+	}}
+
+	tests := []struct {
+		name   string
+		day    Day
+		args   []string
+		output string
+	}{
+		{name: "missing input", day: testDay},
+		{name: "unknown flag", day: testDay, args: []string{"-nope"}},
+		{name: "extra arguments", day: testDay, args: []string{"input"}},
+		{name: "invalid part", day: testDay, args: []string{"-part", "3"}},
+		{name: "failing part", day: failing, output: "Part1 result:  1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			if tt.output != "" {
+				fsys["input"] = &fstest.MapFile{Data: []byte("x\n")}
+			}
+			stdout, _, err := runDay(t, tt.day, tt.args, "", fsys)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if stdout != tt.output {
+				t.Errorf("expected output %q, got %q", tt.output, stdout)
+			}
+		})
+	}
+}
+
+func TestRun_NotImplemented(t *testing.T) {
+	stub := Day{Number: 7, Part1: countLines, Part2: func(context.Context, io.Reader) (int, error) {
+		return 0, ErrNotImplemented
+	}}
+	fsys := fstest.MapFS{"input": {Data: []byte("x\n")}}
+
+	stdout, stderr, err := runDay(t, stub, nil, "", fsys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "Part1 result:  1\n" {
+		t.Errorf("unexpected output %q", stdout)
+	}
+	if stderr != "Part2: not implemented\n" {
+		t.Errorf("unexpected stderr %q", stderr)
+	}
+}
+
+func TestRun_History(t *testing.T) {
+	sum := sha256.Sum256([]byte("a\nb\n"))
+	hash := hex.EncodeToString(sum[:])
+	h := &history.History{}
+	h.Record(history.Attempt{Day: 7, Part: 1, Answer: 2, Outcome: history.TooLow, InputHash: hash})
+	h.Record(history.Attempt{Day: 7, Part: 2, Answer: 5, Outcome: history.Correct, InputHash: hash})
+
+	tests := []struct {
+		name     string
+		input    string
+		warnings []string
+	}{
+		{
+			name:  "puzzle input",
+			input: "a\nb\n",
+			warnings: []string{
+				"warning: day 7 part 1: 2 was already rejected (too low)",
+				"warning: day 7 part 2: 4 differs from the accepted answer 5",
+			},
+		},
+		{name: "other input", input: "a\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := Run(context.Background(), testDay, []string{"-format", "json"}, Env{
+				Stdin:   strings.NewReader(""),
+				Stdout:  &stdout,
+				Stderr:  &stderr,
+				FS:      fstest.MapFS{"input": {Data: []byte(tt.input)}},
+				History: h,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := strings.Join(tt.warnings, "\n")
+			if expected != "" {
+				expected += "\n"
+			}
+			if stderr.String() != expected {
+				t.Errorf("Expected warnings:\n%s\nGot:\n%s", expected, stderr.String())
+			}
+
+			dec := json.NewDecoder(&stdout)
+			for _, warning := range tt.warnings {
+				var res Result
+				if err := dec.Decode(&res); err != nil {
+					t.Fatal(err)
+				}
+				if "warning: "+res.Warning != warning {
+					t.Errorf("result warning %q, want %q", res.Warning, warning)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	slow := Day{Number: 7, Part1: func(ctx context.Context, r io.Reader) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}}
+	fsys := fstest.MapFS{"input": {Data: []byte("x\n")}}
+
+	_, _, err := runDay(t, slow, []string{"-timeout", "10ms"}, "", fsys)
+	if !strings.Contains(fmt.Sprint(err), "part 1 timed out after 10ms") {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("timeout error %v should wrap context.DeadlineExceeded", err)
+	}
+}
+
+func TestRun_Canceled(t *testing.T) {
+	slow := Day{Number: 7, Part1: func(ctx context.Context, r io.Reader) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Run(ctx, slow, nil, Env{
+		Stdin:  strings.NewReader(""),
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+		FS:     fstest.MapFS{"input": {Data: []byte("x\n")}},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestProfileName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "cpu.prof", expected: "cpu.day02.part1.prof"},
+		{name: "trace", expected: "trace.day02.part1"},
+		{name: "out/mem.pprof", expected: "out/mem.day02.part1.pprof"},
+	}
+
+	for _, tt := range tests {
+		if got := profileName(tt.name, 2, 1); got != tt.expected {
+			t.Errorf("profileName(%q) = %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestRun_Profiles(t *testing.T) {
+	dir := t.TempDir()
+	fsys := fstest.MapFS{"input": {Data: []byte("a\nb\n")}}
+	args := []string{
+		"-part", "2",
+		"-cpuprofile", filepath.Join(dir, "cpu.prof"),
+		"-memprofile", filepath.Join(dir, "mem.prof"),
+		"-trace", filepath.Join(dir, "trace.out"),
+	}
+	if _, _, err := runDay(t, testDay, args, "", fsys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+		if info, err := entry.Info(); err != nil || info.Size() == 0 {
+			t.Errorf("%s is empty", entry.Name())
+		}
+	}
+	expected := "cpu.day07.part2.prof mem.day07.part2.prof trace.day07.part2.out"
+	if strings.Join(names, " ") != expected {
+		t.Errorf("wrote %q, want %q", names, expected)
+	}
+
+	if _, _, err := runDay(t, testDay, []string{"-cpuprofile", filepath.Join(dir, "missing", "cpu.prof")}, "", fsys); err == nil {
+		t.Error("expected an error for an unwritable profile")
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := Checkpoint(ctx, 1); err != nil {
+		t.Errorf("Checkpoint should skip most iterations, got %v", err)
+	}
+	if err := Checkpoint(ctx, 2048); !errors.Is(err, context.Canceled) {
+		t.Errorf("Checkpoint(2048) = %v, want context.Canceled", err)
+	}
+	if err := Checkpoint(context.Background(), 0); err != nil {
+		t.Errorf("Checkpoint on a live context = %v", err)
+	}
+}
+
+func TestRun_Progress(t *testing.T) {
+	fsys := fstest.MapFS{"input": {Data: []byte("a\nb\n")}}
+	stdout, stderr, err := runDay(t, testDay, []string{"-progress"}, "", fsys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "Part1 result:  2\nPart2 result:  4\n" {
+		t.Errorf("progress leaked into stdout: %q", stdout)
+	}
+	for _, want := range []string{"Part1: 100% 4 B/4 B", "Part2: 100% 4 B/4 B"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("stderr %q does not contain %q", stderr, want)
+		}
+	}
+}
+
+func TestRun_Format(t *testing.T) {
+	sum := sha256.Sum256([]byte("a\nb\n"))
+	hash := hex.EncodeToString(sum[:])
+	fsys := fstest.MapFS{"input": {Data: []byte("a\nb\n")}}
+
+	t.Run("json", func(t *testing.T) {
+		stdout, _, err := runDay(t, testDay, []string{"-format", "json"}, "", fsys)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		dec := json.NewDecoder(strings.NewReader(stdout))
+		for part, answer := range []int{2, 4} {
+			var res Result
+			if err := dec.Decode(&res); err != nil {
+				t.Fatalf("decoding part %d: %v", part+1, err)
+			}
+			if res.Day != 7 || res.Part != part+1 || res.Answer != answer || res.InputHash != hash || res.Error != "" {
+				t.Errorf("unexpected result %+v", res)
+			}
+		}
+		if dec.More() {
+			t.Errorf("unexpected trailing output in %q", stdout)
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		stdout, _, err := runDay(t, testDay, []string{"-format", "csv", "-part", "2"}, "", fsys)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		records, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 2 {
+			t.Fatalf("expected a header and one row, got %q", records)
+		}
+		if got := strings.Join(records[0], ","); got != "day,part,answer,duration,input_hash,error,warning" {
+			t.Errorf("unexpected header %q", got)
+		}
+		row := records[1]
+		if row[0] != "7" || row[1] != "2" || row[2] != "4" || row[4] != hash || row[5] != "" {
+			t.Errorf("unexpected row %q", row)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		stdout, _, err := runDay(t, testDay, []string{"-format", "json"}, "", fstest.MapFS{})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		var res Result
+		if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+			t.Fatalf("decoding %q: %v", stdout, err)
+		}
+		if res.Part != 1 || res.Error != err.Error() {
+			t.Errorf("unexpected result %+v for error %v", res, err)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		if _, _, err := runDay(t, testDay, []string{"-format", "xml"}, "", fsys); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}